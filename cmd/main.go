@@ -4,17 +4,21 @@ import (
 	"log"
 	"log/slog"
 	"os"
+	"slices"
 	"strconv"
 
 	"github.com/NerdBow/Grinders-API/internal/server"
 )
 
+const MIN_ADMIN_KEY_LENGTH = 32
+
 func main() {
 	// If no fatal is thrown then all env vars are not empty.
 	checkJWTEnv()
 	checkSQLiteEnv()
 	checkArgonEnv()
 	checkHTTPEnv()
+	checkLoginEnv()
 	initilizeLogging()
 
 	server.Run()
@@ -111,6 +115,54 @@ func checkHTTPEnv() {
 	if addr == "" {
 		log.Fatalf("Unable to get \"ADDRESS\" environmnet variable.\nPlease make sure to set it before starting the API.")
 	}
+
+	// TLS_CERT_FILE and TLS_KEY_FILE are optional, but must be set together.
+	certFile := os.Getenv("TLS_CERT_FILE")
+	keyFile := os.Getenv("TLS_KEY_FILE")
+	if (certFile == "") != (keyFile == "") {
+		log.Fatalf("Variables \"TLS_CERT_FILE\" and \"TLS_KEY_FILE\" must both be set to serve over TLS.")
+	}
+	for _, file := range []string{certFile, keyFile} {
+		if _, err := os.Stat(file); file != "" && os.IsNotExist(err) {
+			log.Fatalf("File %s could not be found.", file)
+		}
+	}
+
+	// SHUTDOWN_DRAIN_DELAY is optional, it defaults to 5 seconds.
+	drainDelay := os.Getenv("SHUTDOWN_DRAIN_DELAY")
+	if _, err := strconv.ParseUint(drainDelay, 10, 32); drainDelay != "" && err != nil {
+		log.Fatalf("Variable \"SHUTDOWN_DRAIN_DELAY\" must be a positive integer or 0.")
+	}
+
+	// The CORS variables are optional, no cross origin requests are allowed without CORS_ALLOWED_ORIGINS.
+	credentials := os.Getenv("CORS_ALLOW_CREDENTIALS")
+	if credentials != "" && credentials != "0" && credentials != "1" {
+		log.Fatalf("\"CORS_ALLOW_CREDENTIALS\" environmnet variable must be values 0 (credentials not allowed) or 1 (credentials allowed).")
+	}
+	// Credentials from every origin would let any site make requests as the signed in user.
+	cors := server.NewCORSSettings()
+	if cors.AllowCredentials && slices.Contains(cors.AllowedOrigins, "*") {
+		log.Fatalf("\"CORS_ALLOWED_ORIGINS\" environmnet variable can not allow all origins with \"*\" while \"CORS_ALLOW_CREDENTIALS\" is 1.\nPlease list the allowed origins instead.")
+	}
+}
+
+func checkLoginEnv() {
+	// LOGIN_MAX_FAILURES and LOGIN_LOCKOUT_DURATION are optional, they default to 5 failures and 15 minutes.
+	maxFailures := os.Getenv("LOGIN_MAX_FAILURES")
+	if n, err := strconv.ParseUint(maxFailures, 10, 32); maxFailures != "" && (err != nil || n <= 0) {
+		log.Fatalf("Variable \"LOGIN_MAX_FAILURES\" must be a positive integer greater than 0.")
+	}
+
+	lockoutDuration := os.Getenv("LOGIN_LOCKOUT_DURATION")
+	if n, err := strconv.ParseUint(lockoutDuration, 10, 32); lockoutDuration != "" && (err != nil || n <= 0) {
+		log.Fatalf("Variable \"LOGIN_LOCKOUT_DURATION\" must be a positive integer greater than 0.")
+	}
+
+	// ADMIN_KEY is optional, the admin endpoints are disabled without it.
+	adminKey := os.Getenv("ADMIN_KEY")
+	if adminKey != "" && len(adminKey) < MIN_ADMIN_KEY_LENGTH {
+		log.Fatalf("Variable \"ADMIN_KEY\" must be at least %d characters long.", MIN_ADMIN_KEY_LENGTH)
+	}
 }
 
 func initilizeLogging() {
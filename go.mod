@@ -5,10 +5,8 @@ go 1.25.0
 require (
 	github.com/golang-jwt/jwt/v5 v5.3.0
 	github.com/google/uuid v1.6.0
+	github.com/mattn/go-sqlite3 v1.14.32
 	golang.org/x/crypto v0.42.0
 )
 
-require (
-	github.com/mattn/go-sqlite3 v1.14.32 // indirect
-	golang.org/x/sys v0.36.0 // indirect
-)
+require golang.org/x/sys v0.36.0 // indirect
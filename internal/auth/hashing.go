@@ -4,6 +4,7 @@ import (
 	"context"
 	"crypto"
 	"crypto/rand"
+	"crypto/sha256"
 	"encoding/base64"
 	"fmt"
 	"log/slog"
@@ -140,3 +141,20 @@ func HashRefreshTokenId(tokenId string) string {
 	tokenIdHash := base64.RawStdEncoding.EncodeToString(hashedBytes)
 	return tokenIdHash
 }
+
+// HashToken returns the SHA-256 hash of a random token, so it can be stored and looked up without storing the token.
+// Tokens are random enough that they do not need a salt or a slow hash like passwords do.
+func HashToken(token string) string {
+	hashedBytes := sha256.Sum256([]byte(token))
+	return base64.RawStdEncoding.EncodeToString(hashedBytes[:])
+}
+
+// GenerateToken returns a URL safe token made from length random bytes.
+func GenerateToken(length int) (string, error) {
+	tokenBytes := make([]byte, length)
+	_, err := rand.Read(tokenBytes)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(tokenBytes), nil
+}
@@ -0,0 +1,49 @@
+package auth
+
+import (
+	"os"
+	"strconv"
+	"time"
+)
+
+const (
+	LOGIN_BACKOFF_BASE             = time.Second // Wait after the first failed login, doubled for every failure after it
+	DEFAULT_LOGIN_MAX_FAILURES     = 5
+	DEFAULT_LOGIN_LOCKOUT_DURATION = 15 * time.Minute
+)
+
+type LoginSettings struct {
+	MaxFailures     uint32        // From env var LOGIN_MAX_FAILURES
+	LockoutDuration time.Duration // In minutes for env var LOGIN_LOCKOUT_DURATION
+}
+
+// NewLoginSettings creates a new LoginSettings with its field specified by the related env vars.
+// Env vars that are not set or 0 use the defaults, so logins are never left unthrottled.
+func NewLoginSettings() LoginSettings {
+	settings := LoginSettings{
+		MaxFailures:     DEFAULT_LOGIN_MAX_FAILURES,
+		LockoutDuration: DEFAULT_LOGIN_LOCKOUT_DURATION,
+	}
+	n, _ := strconv.ParseUint(os.Getenv("LOGIN_MAX_FAILURES"), 10, 32)
+	if n > 0 {
+		settings.MaxFailures = uint32(n)
+	}
+	n, _ = strconv.ParseUint(os.Getenv("LOGIN_LOCKOUT_DURATION"), 10, 64)
+	if n > 0 {
+		settings.LockoutDuration = time.Duration(time.Minute * time.Duration(n))
+	}
+	return settings
+}
+
+// Backoff returns how long to wait before another login is allowed after the given amount of consecutive failures.
+// The wait doubles for each failure and is capped at the lockout duration.
+func (s LoginSettings) Backoff(failures uint32) time.Duration {
+	if failures == 0 {
+		return 0
+	}
+	backoff := LOGIN_BACKOFF_BASE
+	for i := uint32(1); i < failures && backoff < s.LockoutDuration; i++ {
+		backoff *= 2
+	}
+	return min(backoff, s.LockoutDuration)
+}
@@ -0,0 +1,69 @@
+package auth
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewLoginSettingsDefaults(t *testing.T) {
+	tests := []struct {
+		name            string
+		maxFailures     string
+		lockoutDuration string
+		want            LoginSettings
+	}{
+		{
+			name: "unset",
+			want: LoginSettings{MaxFailures: DEFAULT_LOGIN_MAX_FAILURES, LockoutDuration: DEFAULT_LOGIN_LOCKOUT_DURATION},
+		},
+		{
+			name:            "zero",
+			maxFailures:     "0",
+			lockoutDuration: "0",
+			want:            LoginSettings{MaxFailures: DEFAULT_LOGIN_MAX_FAILURES, LockoutDuration: DEFAULT_LOGIN_LOCKOUT_DURATION},
+		},
+		{
+			name:            "set",
+			maxFailures:     "3",
+			lockoutDuration: "60",
+			want:            LoginSettings{MaxFailures: 3, LockoutDuration: time.Hour},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Setenv("LOGIN_MAX_FAILURES", test.maxFailures)
+			t.Setenv("LOGIN_LOCKOUT_DURATION", test.lockoutDuration)
+
+			got := NewLoginSettings()
+			if got != test.want {
+				t.Errorf("NewLoginSettings() = %+v, want %+v", got, test.want)
+			}
+			if got.Backoff(1) == 0 {
+				t.Errorf("Backoff(1) = 0, want logins to be throttled")
+			}
+		})
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	settings := LoginSettings{MaxFailures: 5, LockoutDuration: 10 * time.Second}
+
+	tests := []struct {
+		failures uint32
+		want     time.Duration
+	}{
+		{failures: 0, want: 0},
+		{failures: 1, want: time.Second},
+		{failures: 2, want: 2 * time.Second},
+		{failures: 4, want: 8 * time.Second},
+		{failures: 5, want: 10 * time.Second},
+		{failures: 100, want: 10 * time.Second},
+	}
+
+	for _, test := range tests {
+		if got := settings.Backoff(test.failures); got != test.want {
+			t.Errorf("Backoff(%d) = %v, want %v", test.failures, got, test.want)
+		}
+	}
+}
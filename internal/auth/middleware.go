@@ -1,24 +1,31 @@
 package auth
 
 import (
-	"encoding/json"
+	"context"
+	"crypto/subtle"
 	"errors"
+	"log/slog"
 	"net/http"
-	"context"
+	"os"
 
+	"github.com/NerdBow/Grinders-API/internal/util"
 	"github.com/golang-jwt/jwt/v5"
 )
 
 const MIN_TOKEN_LENGTH = 30 // Mainly here so I don't get a string slicing error
 
+type contextKey string
+
+const userIdKey contextKey = "userId"
+
 func AuthMiddleware(h http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		authHeader := r.Header.Get("Authorization")
 		if authHeader == "" || len(authHeader) < MIN_TOKEN_LENGTH {
-			m(w, http.StatusUnauthorized, "Unauthorized request", "No Bearer token")
+			util.WriteProblem(w, r, util.ErrMissingToken)
 			return
 		} else if authHeader[:6] != "Bearer" {
-			m(w, http.StatusUnauthorized, "Unauthorized request", "No Bearer token")
+			util.WriteProblem(w, r, util.ErrMissingToken)
 			return
 		}
 		tokenString := authHeader[7:]
@@ -26,29 +33,42 @@ func AuthMiddleware(h http.HandlerFunc) http.HandlerFunc {
 		if err != nil {
 			switch {
 			case errors.Is(err, jwt.ErrTokenExpired):
-				m(w, http.StatusUnauthorized, "Unauthorized request", "Token is expired")
+				util.WriteProblem(w, r, util.ErrTokenExpired)
 				return
 			default:
-				m(w, http.StatusBadRequest, "Unauthorized request", "Token is malformed")
+				util.WriteProblem(w, r, util.ErrMalformedToken)
 				return
 			}
 		}
 		ctx := r.Context()
-		ctx = context.WithValue(ctx, "userId", accessToken.Sub)
+		ctx = context.WithValue(ctx, userIdKey, accessToken.Sub)
+		logger := util.LoggerFromContext(ctx).With(slog.Uint64("userId", accessToken.Sub))
+		ctx = util.WithLogger(ctx, logger)
 		h(w, r.WithContext(ctx))
 	}
 }
 
-// This function is temperary probably going to move it into a different package
-func m(w http.ResponseWriter, statusCode int, error string, message string) {
-	response := struct {
-		Error string `json:"error"`
-		Message string `json:"message"`
-	}{error, message}
-	responseBytes, _ := json.Marshal(response)
-	w.WriteHeader(statusCode)
-	_, err := w.Write(responseBytes)
-	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
+// UserIdFromContext returns the user id that AuthMiddleware stored in the context.
+// If the request did not go through AuthMiddleware 0 is returned.
+func UserIdFromContext(ctx context.Context) uint64 {
+	userId, _ := ctx.Value(userIdKey).(uint64)
+	return userId
+}
+
+// AdminMiddleware only lets requests through that send the admin key of the env var ADMIN_KEY in the X-Admin-Key header.
+// If ADMIN_KEY is not set all admin requests are rejected.
+func AdminMiddleware(h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		adminKey := os.Getenv("ADMIN_KEY")
+		if adminKey == "" {
+			util.WriteProblem(w, r, util.ErrAdminDisabled)
+			return
+		}
+		if subtle.ConstantTimeCompare([]byte(r.Header.Get("X-Admin-Key")), []byte(adminKey)) != 1 {
+			util.LoggerFromContext(r.Context()).Warn("AdminMiddleware invalid admin key")
+			util.WriteProblem(w, r, util.ErrInvalidAdminKey)
+			return
+		}
+		h(w, r)
 	}
 }
@@ -92,6 +92,16 @@ func (s *TokenSettings) CreateRefreshToken(userId uint64) (RefreshToken, error)
 
 // ParseToken parses a given JWT token string and returns a JWTToken of the parsed token claims.
 func ParseToken(tokenString string) (JWTToken, error) {
+	return parseToken(tokenString)
+}
+
+// ParseExpiredToken parses a given JWT token string the same way as ParseToken, but it will not reject the token for being expired.
+// The signature of the token is still verified, so this is used to identify the user of a refresh or logout request.
+func ParseExpiredToken(tokenString string) (JWTToken, error) {
+	return parseToken(tokenString, jwt.WithoutClaimsValidation())
+}
+
+func parseToken(tokenString string, opts ...jwt.ParserOption) (JWTToken, error) {
 	keyFunc := func(t *jwt.Token) (any, error) {
 		key := os.Getenv("JWT_SIGNING_KEY")
 		return []byte(key), nil
@@ -99,7 +109,8 @@ func ParseToken(tokenString string) (JWTToken, error) {
 
 	claims := jwt.RegisteredClaims{}
 
-	_, err := jwt.ParseWithClaims(tokenString, &claims, keyFunc, jwt.WithValidMethods([]string{"HS256"}))
+	opts = append(opts, jwt.WithValidMethods([]string{"HS256"}))
+	_, err := jwt.ParseWithClaims(tokenString, &claims, keyFunc, opts...)
 	if err != nil {
 		slog.LogAttrs(context.Background(), slog.LevelDebug, "ParseWithClaims ParseToken", slog.String("err", err.Error()))
 		return JWTToken{}, err
@@ -1,75 +1,379 @@
 package database
 
 import (
+	"context"
 	"log/slog"
+	"time"
 
 	"github.com/NerdBow/Grinders-API/internal/util"
 )
 
+type HealthDB interface {
+	// PingContext will check that a connection to the database can be made.
+	PingContext(ctx context.Context) error
+	// CheckSchema will check that all the tables of the API exist.
+	// If any are missing ErrSchemaMissing will be returned.
+	CheckSchema(ctx context.Context, logger *slog.Logger) error
+}
+
 type SessionsDB interface {
 	// AddSession will inserts the given session information into the database.
-	AddSession(logger *slog.Logger, session util.Session) error
+	AddSession(ctx context.Context, logger *slog.Logger, session util.Session) error
 	// GetSession will query a session with the specified hashedId and userId.
 	// If there is no session then an empty Session will be returned.
-	GetSession(logger *slog.Logger, hashedId string, userId uint64) (util.Session, error)
+	GetSession(ctx context.Context, logger *slog.Logger, hashedId string, userId uint64) (util.Session, error)
 	// DeleteSesssion will delete a session specified by the given hashedId.
 	// If unable to delete the session an error will be returned.
-	DeleteSession(logger *slog.Logger, hashedId string) error
+	DeleteSession(ctx context.Context, logger *slog.Logger, hashedId string) error
+}
+
+type LoginAttemptsDB interface {
+	// AddLoginFailure will count a failed login for key at now, unless the key is locked at now.
+	// The key is then locked until the time in lockedUntil at the amount of failures minus one, or the last time in lockedUntil if there are more failures.
+	// The attempts of all keys that are no longer locked and whose last failure was before resetBefore are deleted first,
+	// so their failures are counted from zero again.
+	// If the key is locked then the recorded attempt is returned with ErrLoginThrottled.
+	AddLoginFailure(ctx context.Context, logger *slog.Logger, key string, now time.Time, resetBefore time.Time, lockedUntil []time.Time) (util.LoginAttempt, error)
+	// RemoveLoginFailure will undo the failure AddLoginFailure returned the attempt of.
+	// The lock of the attempt is lifted if no failures were counted for its key after it.
+	RemoveLoginFailure(ctx context.Context, logger *slog.Logger, attempt util.LoginAttempt) error
+	// DeleteLoginAttempt will delete the failed login attempts recorded for key.
+	// No error will be returned if there are none.
+	DeleteLoginAttempt(ctx context.Context, logger *slog.Logger, key string) error
 }
 
 type UsersDB interface {
 	// AddUser will insert the given user information into the database.
-	AddUser(logger *slog.Logger, user util.User) error
+	AddUser(ctx context.Context, logger *slog.Logger, user util.User) error
 	// GetUser will query a user with the specified userId.
 	// If there is no user with the userId, then an error will be returned.
-	GetUser(logger *slog.Logger, userId uint64) (util.User, error)
+	GetUser(ctx context.Context, logger *slog.Logger, userId uint64) (util.User, error)
 	// GetUserByUsername will query a user with the specified username.
 	// If there is no user with the userId, then an error will be returned.
-	GetUserByUsername(logger *slog.Logger, username string) (util.User, error)
+	GetUserByUsername(ctx context.Context, logger *slog.Logger, username string) (util.User, error)
 	// EditUsername will change the username of the user specified by userId to the given newName.
 	// If there is no user with the userId, then no error will be returned.
 	// Errors are only returned for database errors.
-	EditUsername(logger *slog.Logger, userId uint64, newName string) error
+	EditUsername(ctx context.Context, logger *slog.Logger, userId uint64, newName string) error
+}
+
+type UserSettingsDB interface {
+	// GetUserSettings will retrive the settings of the user.
+	// If the user has no settings, then settings with zero fields will be returned.
+	GetUserSettings(ctx context.Context, logger *slog.Logger, userId uint64) (util.UserSettings, error)
+	// SetUserSettings will create or replace the settings of the user specified in the settings struct.
+	SetUserSettings(ctx context.Context, logger *slog.Logger, settings util.UserSettings) error
 }
 
 type CategoriesDB interface {
 	// AddCategory will create a new category with the specified name for the userId.
-	AddCategory(logger *slog.Logger, name string, userId uint64) error
+	// If the user already has a category with the name, then ErrCategoryExists will be returned.
+	AddCategory(ctx context.Context, logger *slog.Logger, name string, userId uint64) error
 	// GetCategory will retrive the specific category specified by name.
-	GetCategory(logger *slog.Logger, name string, userId uint64) (util.Category, error)
+	// If there is no category with the name, then ErrCategoryNotFound will be returned.
+	GetCategory(ctx context.Context, logger *slog.Logger, name string, userId uint64) (util.Category, error)
+	// GetCategoryById will retrive the specific category specified by categoryId.
+	// If there is no category with the categoryId, then ErrCategoryNotFound will be returned.
+	GetCategoryById(ctx context.Context, logger *slog.Logger, categoryId uint64, userId uint64) (util.Category, error)
 	// QueryCategory will retrive ALL categories prefixed with the specified prefix.
-	QueryCategory(logger *slog.Logger, prefix string, userId uint64) ([]util.Category, error)
+	QueryCategory(ctx context.Context, logger *slog.Logger, prefix string, userId uint64) ([]util.Category, error)
 	// GetAllUserCategories will retrive all categories linked to the userId.
 	// The slice of Category structs will be sorted in alphabetical order by category name.
-	GetUserCategories(logger *slog.Logger, userId uint64) ([]util.Category, error)
+	GetUserCategories(ctx context.Context, logger *slog.Logger, userId uint64) ([]util.Category, error)
 	// EditCategoryName will change the name of the category for categoryId to newName.
-	EditCategoryName(logger *slog.Logger, categoryId uint64, newName string, userId uint64) error
+	// If there is no category with the categoryId, then ErrCategoryNotFound will be returned.
+	// If the user already has another category with newName, then ErrCategoryExists will be returned.
+	EditCategoryName(ctx context.Context, logger *slog.Logger, categoryId uint64, newName string, userId uint64) error
 	// DeleteCategory will delete the category with the specified categoryId.
-	DeleteCategory(logger *slog.Logger, categoryId uint64, userId uint64) error
+	// If there is no category with the categoryId, then ErrCategoryNotFound will be returned.
+	DeleteCategory(ctx context.Context, logger *slog.Logger, categoryId uint64, userId uint64) error
 }
 
 type TasksDB interface {
 	// AddTask will create a new task in the database with the specified fields in the task struct.
-	AddTask(logger *slog.Logger, task util.Task) error
+	// The id of the new task is returned.
+	AddTask(ctx context.Context, logger *slog.Logger, task util.Task) (uint64, error)
 	// GetTask will retrive a specific task by the given taskId.
-	GetTask(logger *slog.Logger, taskId uint64, userId uint64) (util.Task, error)
+	// If there is no task with the taskId, then ErrTaskNotFound will be returned.
+	GetTask(ctx context.Context, logger *slog.Logger, taskId uint64, userId uint64) (util.Task, error)
 	// QUeryTask will retrives all the task that match the provided querySettings.
-	QueryTask(logger *slog.Logger, querySettings util.TaskQuerySettings) ([]util.Task, error)
+	QueryTask(ctx context.Context, logger *slog.Logger, querySettings util.TaskQuerySettings) ([]util.Task, error)
 	// EditTask will edit a task specific by the id of the task struct.
 	// All fields that are in the task struct that are not the defualt 0 values will be changed in the database.
 	// IsComplete will not be edited. SetTaskCompletion to mark a task as complete.
-	EditTask(logger *slog.Logger, task util.Task) error
+	// If there is no task with the id, then ErrTaskNotFound will be returned.
+	EditTask(ctx context.Context, logger *slog.Logger, task util.Task) error
 	// DeleteTask will delete the task with the specified taskId.
-	DeleteTask(logger *slog.Logger, taskId uint64, userId uint64) error
-	//SetTaskCompletion will edit the task's is_complete column to the specific status
-	SetTaskCompletion(logger *slog.Logger, taskId uint64, status bool, userId uint64) error
-}
-
-type (
-	WorkLogsDB     interface{}
-	PausesDB       interface{}
-	BreaksDB       interface{}
-	GoalsDB        interface{}
-	GroupsDB       interface{}
-	GroupMembersDB interface{}
-)
+	// If there is no task with the taskId, then ErrTaskNotFound will be returned.
+	DeleteTask(ctx context.Context, logger *slog.Logger, taskId uint64, userId uint64) error
+	//SetTaskCompletion will edit the task's is_complete column to the specific status and its completion_time to completionTime.
+	// If there is no task with the taskId, then ErrTaskNotFound will be returned.
+	SetTaskCompletion(ctx context.Context, logger *slog.Logger, taskId uint64, status bool, completionTime time.Time, userId uint64) error
+}
+
+type WorkLogsDB interface {
+	// AddWorkLog will create a new work log in the database with the specified fields in the workLog struct.
+	// The id of the new work log is returned.
+	// If the work log is running and the user already has a running work log, then ErrWorkLogConflict will be returned.
+	AddWorkLog(ctx context.Context, logger *slog.Logger, workLog util.WorkLog) (uint64, error)
+	// SwitchWorkLog will stop the running work log and end its running pause in the same way as StopWorkLog and EndPause,
+	// then create the new running workLog, all in one transaction. The running pause is skipped if its id is 0.
+	// The id of the new work log is returned.
+	// If the stopped work log is no longer running or another work log was started, then ErrWorkLogConflict will be returned.
+	SwitchWorkLog(ctx context.Context, logger *slog.Logger, stopped util.WorkLog, pause util.Pause, workLog util.WorkLog) (uint64, error)
+	// AddCompletedWorkLog will create the completed workLog if it does not overlap any other work log of the user,
+	// checking and creating in one transaction. Running work logs overlap everything after their start time.
+	// The id of the new work log is returned, or the overlapping work logs ordered by start time if there are any.
+	AddCompletedWorkLog(ctx context.Context, logger *slog.Logger, workLog util.WorkLog) (uint64, []util.WorkLog, error)
+	// SplitWorkLog will shorten the completed first work log to its new end time and create the second work log in one transaction.
+	// The pauses are saved on the work log they are in, pauses with an id of 0 are created.
+	// Breaks of the first work log that start at or after the start of the second are moved to the second.
+	// The id of the second work log is returned.
+	// If the first work log is not completed, then ErrWorkLogNotFound will be returned.
+	SplitWorkLog(ctx context.Context, logger *slog.Logger, first util.WorkLog, second util.WorkLog, firstPauses []util.Pause, secondPauses []util.Pause) (uint64, error)
+	// MergeWorkLogs will update the completed merged work log and move the pauses and breaks of the other work logs onto it,
+	// then delete the other work logs, all in one transaction. The gapPauses are created on the merged work log.
+	// If any work log other than the merged ones starts within the merged work log, then ErrMergeNotAdjacent will be returned.
+	// If any of the work logs needs a review, then a ConflictError of ErrMergeReviewPending with their ids will be returned.
+	// If any of the work logs is not completed, then ErrWorkLogNotFound will be returned.
+	MergeWorkLogs(ctx context.Context, logger *slog.Logger, merged util.WorkLog, otherIds []uint64, gapPauses []util.Pause) error
+	// GetRunningWorkLog will retrive the work log of the user that has not been stopped yet.
+	// If there is no running work log, then ErrWorkLogNotFound will be returned.
+	GetRunningWorkLog(ctx context.Context, logger *slog.Logger, userId uint64) (util.WorkLog, error)
+	// GetWorkLog will retrive a specific work log by the given workLogId.
+	// If there is no work log with the workLogId, then ErrWorkLogNotFound will be returned.
+	GetWorkLog(ctx context.Context, logger *slog.Logger, workLogId uint64, userId uint64) (util.WorkLog, error)
+	// QueryWorkLogs will retrive all the work logs that match the provided querySettings, ordered by start time.
+	QueryWorkLogs(ctx context.Context, logger *slog.Logger, querySettings util.WorkLogQuerySettings) ([]util.WorkLog, error)
+	// StopWorkLog will mark the running work log specified by the id of the workLog struct as complete
+	// and set its work description, end time and duration.
+	// If there is no running work log with the id, then ErrWorkLogNotFound will be returned.
+	StopWorkLog(ctx context.Context, logger *slog.Logger, workLog util.WorkLog) error
+	// GetWorkLogsBetween will retrive all work logs of the user that overlap the time range from to, ordered by start time.
+	// Running work logs overlap everything after their start time.
+	GetWorkLogsBetween(ctx context.Context, logger *slog.Logger, userId uint64, from time.Time, to time.Time) ([]util.WorkLog, error)
+}
+
+type PausesDB interface {
+	// AddPause will create a new pause in the database with the specified fields in the pause struct.
+	// The id of the new pause is returned.
+	// If the work log already has a running pause, then ErrWorkLogPaused will be returned.
+	AddPause(ctx context.Context, logger *slog.Logger, pause util.Pause) (uint64, error)
+	// GetRunningPause will retrive the pause of the work log that has not ended yet.
+	// If there is no running pause, then ErrWorkLogNotPaused will be returned.
+	GetRunningPause(ctx context.Context, logger *slog.Logger, workLogId uint64) (util.Pause, error)
+	// GetPauses will retrive all pauses of the work log ordered by start time.
+	GetPauses(ctx context.Context, logger *slog.Logger, workLogId uint64) ([]util.Pause, error)
+	// EndPause will set the end time and duration of the running pause specified by the id of the pause struct.
+	// If there is no running pause with the id, then ErrWorkLogNotPaused will be returned.
+	EndPause(ctx context.Context, logger *slog.Logger, pause util.Pause) error
+}
+
+type BreaksDB interface {
+	// AddBreak will create a new break in the database with the specified fields in the brk struct.
+	// The id of the new break is returned.
+	// If the user of the work log already has a running break, then ErrBreakRunning will be returned.
+	// If the work log does not exist, then ErrWorkLogNotFound will be returned.
+	AddBreak(ctx context.Context, logger *slog.Logger, brk util.Break) (uint64, error)
+	// GetBreak will retrive a specific break by the given breakId.
+	// If there is no break with the breakId on a work log of the user, then ErrBreakNotFound will be returned.
+	GetBreak(ctx context.Context, logger *slog.Logger, breakId uint64, userId uint64) (util.Break, error)
+	// GetRunningBreak will retrive the break of the user that has not ended yet.
+	// If there is no running break, then ErrBreakNotFound will be returned.
+	GetRunningBreak(ctx context.Context, logger *slog.Logger, userId uint64) (util.Break, error)
+	// QueryBreaks will retrive all breaks of the user that overlap the time range from to, ordered by start time.
+	// Breaks that are still running overlap everything after their start time.
+	QueryBreaks(ctx context.Context, logger *slog.Logger, userId uint64, from time.Time, to time.Time) ([]util.Break, error)
+	// EndBreak will set the end time and duration of the running break specified by the id of the brk struct.
+	// If there is no running break with the id, then ErrBreakNotFound will be returned.
+	EndBreak(ctx context.Context, logger *slog.Logger, brk util.Break) error
+}
+
+type PomodorosDB interface {
+	// GetPomodoroSettings will retrive the Pomodoro settings of the user.
+	// If the user has no settings, then settings with zero lengths will be returned.
+	GetPomodoroSettings(ctx context.Context, logger *slog.Logger, userId uint64) (util.PomodoroSettings, error)
+	// SetPomodoroSettings will create or replace the Pomodoro settings of the user specified in the settings struct.
+	SetPomodoroSettings(ctx context.Context, logger *slog.Logger, settings util.PomodoroSettings) error
+	// GetPomodoro will retrive the Pomodoro cycle of the user.
+	// If the user has no Pomodoro cycle, then ErrPomodoroNotFound will be returned.
+	GetPomodoro(ctx context.Context, logger *slog.Logger, userId uint64) (util.Pomodoro, error)
+	// GetDuePomodoros will retrive all Pomodoro cycles that are not idle and whose phase ended at or before now.
+	GetDuePomodoros(ctx context.Context, logger *slog.Logger, now time.Time) ([]util.Pomodoro, error)
+	// AddPomodoro will create the Pomodoro cycle of the user specified in the pomodoro struct.
+	// If the user already has a Pomodoro cycle, then ErrPomodoroChanged will be returned.
+	AddPomodoro(ctx context.Context, logger *slog.Logger, pomodoro util.Pomodoro) error
+	// UpdatePomodoro will replace the Pomodoro cycle of the user specified in the pomodoro struct.
+	// If the stored cycle no longer has the phase and phase end of previous, then ErrPomodoroChanged will be returned.
+	UpdatePomodoro(ctx context.Context, logger *slog.Logger, pomodoro util.Pomodoro, previous util.Pomodoro) error
+	// DeletePomodoro will delete the Pomodoro cycle of the user specified in the pomodoro struct.
+	// If the user has no Pomodoro cycle with the phase and phase end of the pomodoro struct, then ErrPomodoroChanged will be returned.
+	DeletePomodoro(ctx context.Context, logger *slog.Logger, pomodoro util.Pomodoro) error
+}
+
+type HeartbeatsDB interface {
+	// GetHeartbeat will retrive the heartbeat of the user.
+	// If the user has never sent a heartbeat, then a heartbeat with zero fields will be returned.
+	GetHeartbeat(ctx context.Context, logger *slog.Logger, userId uint64) (util.Heartbeat, error)
+	// SetHeartbeat will create or replace the heartbeat of the user specified in the heartbeat struct.
+	SetHeartbeat(ctx context.Context, logger *slog.Logger, heartbeat util.Heartbeat) error
+	// GetIdleHeartbeats will retrive the heartbeats that were sent and became idle at or before now,
+	// of users who have a running work log that is not paused.
+	GetIdleHeartbeats(ctx context.Context, logger *slog.Logger, now time.Time) ([]util.Heartbeat, error)
+}
+
+type TimerLimitsDB interface {
+	// GetTimerLimits will retrive the timer limits of the user.
+	// If the user has no limits, then limits with zero fields will be returned.
+	GetTimerLimits(ctx context.Context, logger *slog.Logger, userId uint64) (util.TimerLimits, error)
+	// SetTimerLimits will create or replace the timer limits of the user specified in the limits struct.
+	SetTimerLimits(ctx context.Context, logger *slog.Logger, limits util.TimerLimits) error
+	// GetLimitedWorkLogs will retrive the running work logs of all users who have a timer limit.
+	GetLimitedWorkLogs(ctx context.Context, logger *slog.Logger) ([]util.WorkLog, error)
+	// AutoStopWorkLog will stop the running work log and end its running pause in the same way as StopWorkLog and EndPause,
+	// then add it to the work logs that need a review, all in one transaction. The running pause is skipped if its id is 0.
+	// If the work log is no longer running, then ErrWorkLogNotFound will be returned.
+	AutoStopWorkLog(ctx context.Context, logger *slog.Logger, stopped util.WorkLog, pause util.Pause, reason string) error
+	// GetWorkLogReviews will retrive all work logs of the user that need a review, ordered by start time.
+	GetWorkLogReviews(ctx context.Context, logger *slog.Logger, userId uint64) ([]util.WorkLogReview, error)
+	// GetWorkLogReview will retrive the review of the work log of the user.
+	// If the work log does not need a review, then ErrReviewNotFound will be returned.
+	GetWorkLogReview(ctx context.Context, logger *slog.Logger, workLogId uint64, userId uint64) (util.WorkLogReview, error)
+	// ResolveWorkLogReview will set the work description, end time and duration of the reviewed workLog
+	// and remove it from the work logs that need a review in one transaction.
+	// If the work log does not need a review, then ErrReviewNotFound will be returned.
+	ResolveWorkLogReview(ctx context.Context, logger *slog.Logger, workLog util.WorkLog) error
+}
+
+type GoalsDB interface {
+	// AddGoal will create a new goal in the database with the specified fields in the goal struct.
+	// The id of the new goal is returned.
+	AddGoal(ctx context.Context, logger *slog.Logger, goal util.Goal) (uint64, error)
+	// GetGoal will retrive a specific goal by the given goalId.
+	// If there is no goal with the goalId on a category of the user, then ErrGoalNotFound will be returned.
+	GetGoal(ctx context.Context, logger *slog.Logger, goalId uint64, userId uint64) (util.Goal, error)
+	// GetUserGoals will retrive all goals on categories of the user.
+	GetUserGoals(ctx context.Context, logger *slog.Logger, userId uint64) ([]util.Goal, error)
+	// EditGoal will change the category, value and type of the goal specified by the id of the goal struct.
+	// If there is no goal with the id on a category of the user, then ErrGoalNotFound will be returned.
+	EditGoal(ctx context.Context, logger *slog.Logger, goal util.Goal, userId uint64) error
+	// DeleteGoal will delete the goal specified by goalId.
+	// If there is no goal with the goalId on a category of the user, then ErrGoalNotFound will be returned.
+	DeleteGoal(ctx context.Context, logger *slog.Logger, goalId uint64, userId uint64) error
+	// GetCategoryWorkLogs will retrive all work logs of the user on tasks of the category that overlap the time range from to,
+	// ordered by start time. Running work logs overlap everything after their start time.
+	GetCategoryWorkLogs(ctx context.Context, logger *slog.Logger, userId uint64, categoryId uint64, from time.Time, to time.Time) ([]util.WorkLog, error)
+	// CountCompletedTasks will count the completed tasks of the user in the category with a completion time within the time range from to.
+	CountCompletedTasks(ctx context.Context, logger *slog.Logger, userId uint64, categoryId uint64, from time.Time, to time.Time) (int64, error)
+	// GetDueTasks will retrive the tasks of the user in the category with a deadline within the time range from to, ordered by deadline.
+	GetDueTasks(ctx context.Context, logger *slog.Logger, userId uint64, categoryId uint64, from time.Time, to time.Time) ([]util.Task, error)
+	// GetCategoryStart will retrive the earliest creation time of a task or start time of a work log of the user in the category.
+	// If the category has no tasks, then the zero time will be returned.
+	GetCategoryStart(ctx context.Context, logger *slog.Logger, userId uint64, categoryId uint64) (time.Time, error)
+}
+
+type GroupInvitesDB interface {
+	// AddGroupInvite will create a new invite in the database with the specified fields in the invite struct.
+	// The id of the new invite is returned.
+	AddGroupInvite(ctx context.Context, logger *slog.Logger, invite util.GroupInvite) (uint64, error)
+	// GetGroupInvites will retrive the invites of the group that have not expired or been used up by now, ordered by creation time.
+	GetGroupInvites(ctx context.Context, logger *slog.Logger, groupId uint64, now time.Time) ([]util.GroupInvite, error)
+	// DeleteGroupInvite will delete the invite with the inviteId of the group.
+	// If there is no invite with the inviteId on the group, then ErrInviteNotFound will be returned.
+	DeleteGroupInvite(ctx context.Context, logger *slog.Logger, inviteId uint64, groupId uint64) error
+	// RedeemGroupInvite will add the user to the group of the invite with the hashedCode and count the use of the invite in one transaction.
+	// The id of the group is returned.
+	// If there is no invite with the hashedCode that has not expired or been used up by now, then ErrInviteNotFound will be returned.
+	// If the user is already a member of the group, then ErrAlreadyMember will be returned and the use is not counted.
+	RedeemGroupInvite(ctx context.Context, logger *slog.Logger, hashedCode string, userId uint64, now time.Time) (uint64, error)
+}
+
+type StreaksDB interface {
+	// GetStreaks will retrive all stored streaks of the user.
+	GetStreaks(ctx context.Context, logger *slog.Logger, userId uint64) ([]util.Streak, error)
+	// SaveStreak will create or replace the streak of the user and goal in the streak struct.
+	SaveStreak(ctx context.Context, logger *slog.Logger, streak util.Streak) error
+	// ResetStreaks will delete the streaks of the user that were counted past from, so they are counted again from the start.
+	ResetStreaks(ctx context.Context, logger *slog.Logger, userId uint64, from time.Time) error
+	// DeleteGoalStreak will delete the streak of the goal specified by goalId if there is one.
+	DeleteGoalStreak(ctx context.Context, logger *slog.Logger, goalId uint64) error
+}
+
+type RestDaysDB interface {
+	// AddRestDay will mark the date, formatted as 2006-01-02, as a rest day of the user. Adding a rest day twice does nothing.
+	AddRestDay(ctx context.Context, logger *slog.Logger, userId uint64, date string) error
+	// GetRestDays will retrive the rest days of the user from the date from up to and including the date to, ordered by date.
+	// An empty from or to is not bounded.
+	GetRestDays(ctx context.Context, logger *slog.Logger, userId uint64, from string, to string) ([]string, error)
+	// DeleteRestDay will delete the rest day of the user on the date.
+	// If the date is not a rest day of the user, then ErrRestDayNotFound will be returned.
+	DeleteRestDay(ctx context.Context, logger *slog.Logger, userId uint64, date string) error
+}
+
+type GroupsDB interface {
+	// AddGroup will create a new group with the specified fields in the group struct and add its owner as a member in one transaction.
+	// The id of the new group is returned.
+	AddGroup(ctx context.Context, logger *slog.Logger, group util.Group) (uint64, error)
+	// GetGroup will retrive a specific group by the given groupId.
+	// If there is no group with the groupId that the user is a member of, then ErrGroupNotFound will be returned.
+	GetGroup(ctx context.Context, logger *slog.Logger, groupId uint64, userId uint64) (util.Group, error)
+	// GetUserGroups will retrive all groups the user is a member of, ordered by name.
+	GetUserGroups(ctx context.Context, logger *slog.Logger, userId uint64) ([]util.Group, error)
+	// EditGroupName will change the name of the group for groupId to newName.
+	// If there is no group with the groupId owned by the ownerId, then ErrGroupNotFound will be returned.
+	EditGroupName(ctx context.Context, logger *slog.Logger, groupId uint64, newName string, ownerId uint64) error
+	// DeleteGroup will delete the group with the specified groupId and all of its members, invites and shared categories in one transaction.
+	// If there is no group with the groupId owned by the ownerId, then ErrGroupNotFound will be returned.
+	DeleteGroup(ctx context.Context, logger *slog.Logger, groupId uint64, ownerId uint64) error
+	// TransferGroupOwnership will make the member newOwnerId the owner of the group and the old owner an admin in one transaction.
+	// If there is no group with the groupId owned by the ownerId, then ErrGroupNotFound will be returned.
+	// If newOwnerId is not a member of the group, then ErrMemberNotFound will be returned.
+	TransferGroupOwnership(ctx context.Context, logger *slog.Logger, groupId uint64, ownerId uint64, newOwnerId uint64) error
+}
+
+type GroupMembersDB interface {
+	// AddGroupMember will add the user of the member struct to its group with the role of the member struct.
+	// The id of the new member is returned.
+	// If the user is already a member of the group, then ErrAlreadyMember will be returned.
+	AddGroupMember(ctx context.Context, logger *slog.Logger, member util.GroupMember) (uint64, error)
+	// GetGroupMembers will retrive all members of the group with their usernames, ordered by username.
+	GetGroupMembers(ctx context.Context, logger *slog.Logger, groupId uint64) ([]util.GroupMember, error)
+	// GetGroupMember will retrive the membership of the user in the group.
+	// If the user is not a member of the group, then ErrMemberNotFound will be returned.
+	GetGroupMember(ctx context.Context, logger *slog.Logger, groupId uint64, userId uint64) (util.GroupMember, error)
+	// SetMemberRole will change the role of the user in the group.
+	// If the user is not a member of the group, then ErrMemberNotFound will be returned.
+	SetMemberRole(ctx context.Context, logger *slog.Logger, groupId uint64, userId uint64, role util.GroupRole) error
+	// DeleteGroupMember will remove the user from the group.
+	// If the user is not a member of the group, then ErrMemberNotFound will be returned.
+	DeleteGroupMember(ctx context.Context, logger *slog.Logger, groupId uint64, userId uint64) error
+	// GetMemberFocusTime will sum the focused time of the work logs of the user within the time range from to, without their pauses.
+	// Work logs that overlap the range only count the time inside it and running work logs count up to to, which must not be after now.
+	GetMemberFocusTime(ctx context.Context, logger *slog.Logger, userId uint64, from time.Time, to time.Time) (int64, error)
+}
+
+type GroupCategoriesDB interface {
+	// AddGroupCategory will create a new shared category with the specified fields in the category struct.
+	// The id of the new category is returned.
+	AddGroupCategory(ctx context.Context, logger *slog.Logger, category util.GroupCategory) (uint64, error)
+	// GetGroupCategories will retrive all shared categories of the group, ordered by name.
+	GetGroupCategories(ctx context.Context, logger *slog.Logger, groupId uint64) ([]util.GroupCategory, error)
+	// EditGroupCategoryName will change the name of the shared category for categoryId to newName.
+	// If there is no shared category with the categoryId in the group, then ErrGroupCategoryNotFound will be returned.
+	EditGroupCategoryName(ctx context.Context, logger *slog.Logger, categoryId uint64, newName string, groupId uint64) error
+	// DeleteGroupCategory will delete the shared category with the specified categoryId.
+	// If there is no shared category with the categoryId in the group, then ErrGroupCategoryNotFound will be returned.
+	DeleteGroupCategory(ctx context.Context, logger *slog.Logger, categoryId uint64, groupId uint64) error
+}
+
+type LeaderboardsDB interface {
+	// GetLeaderboard will rank the members of the group by their focused time within the time range from to, measured like GetMemberFocusTime,
+	// with the amount of tasks they completed within the time range breaking ties. Members with the same totals share a rank.
+	// Running work logs count up to to, which must not be after now.
+	// Members that opted out of leaderboards are left out.
+	GetLeaderboard(ctx context.Context, logger *slog.Logger, groupId uint64, from time.Time, to time.Time) ([]util.LeaderboardEntry, error)
+	// GetLeaderboardOptOut will report if the user opted out of leaderboards.
+	GetLeaderboardOptOut(ctx context.Context, logger *slog.Logger, userId uint64) (bool, error)
+	// SetLeaderboardOptOut will opt the user out of leaderboards or back in.
+	SetLeaderboardOptOut(ctx context.Context, logger *slog.Logger, userId uint64, optOut bool) error
+}
@@ -0,0 +1,122 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/NerdBow/Grinders-API/internal/util"
+)
+
+const breakColumns = "b.id, b.start_time, b.duration, b.end_time, b.work_log_id"
+
+func scanBreak(row scanner) (util.Break, error) {
+	brk := util.Break{}
+	err := row.Scan(&brk.Id, &brk.StartTime, &brk.Duration, &brk.EndTime, &brk.WorkLogId)
+	return brk, err
+}
+
+func (db *SQLiteDB) AddBreak(ctx context.Context, logger *slog.Logger, brk util.Break) (uint64, error) {
+	query := "INSERT INTO breaks (start_time, duration, end_time, work_log_id, user_id) SELECT ?, ?, ?, id, user_id FROM work_logs WHERE id = ?;"
+
+	result, err := db.ExecContext(ctx, query, brk.StartTime, brk.Duration, brk.EndTime, brk.WorkLogId)
+	if isUniqueViolation(err) {
+		return 0, util.ErrBreakRunning
+	}
+	if err != nil {
+		return 0, queryError(ctx, logger, "Exec AddBreak", err)
+	}
+
+	n, err := result.RowsAffected()
+	if err != nil {
+		logger.LogAttrs(ctx, slog.LevelWarn, "RowsAffected AddBreak", slog.String("err", err.Error()))
+	}
+
+	if n != 1 {
+		logger.LogAttrs(ctx, slog.LevelWarn, "RowsAffected AddBreak", slog.String("err", "There were no rows affected"))
+		return 0, util.ErrWorkLogNotFound
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, queryError(ctx, logger, "LastInsertId AddBreak", err)
+	}
+
+	return uint64(id), nil
+}
+
+func (db *SQLiteDB) GetBreak(ctx context.Context, logger *slog.Logger, breakId uint64, userId uint64) (util.Break, error) {
+	query := "SELECT " + breakColumns + " FROM breaks b INNER JOIN work_logs w ON b.work_log_id = w.id WHERE b.id = ? AND w.user_id = ?;"
+	row := db.QueryRowContext(ctx, query, breakId, userId)
+
+	brk, err := scanBreak(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return brk, util.ErrBreakNotFound
+	}
+	if err != nil {
+		return brk, queryError(ctx, logger, "Scan GetBreak", err)
+	}
+	return brk, nil
+}
+
+func (db *SQLiteDB) GetRunningBreak(ctx context.Context, logger *slog.Logger, userId uint64) (util.Break, error) {
+	query := "SELECT " + breakColumns + " FROM breaks b INNER JOIN work_logs w ON b.work_log_id = w.id WHERE w.user_id = ? AND b.end_time = ?;"
+	row := db.QueryRowContext(ctx, query, userId, time.Time{})
+
+	brk, err := scanBreak(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return brk, util.ErrBreakNotFound
+	}
+	if err != nil {
+		return brk, queryError(ctx, logger, "Scan GetRunningBreak", err)
+	}
+	return brk, nil
+}
+
+func (db *SQLiteDB) QueryBreaks(ctx context.Context, logger *slog.Logger, userId uint64, from time.Time, to time.Time) ([]util.Break, error) {
+	query := `SELECT ` + breakColumns + ` FROM breaks b INNER JOIN work_logs w ON b.work_log_id = w.id
+	WHERE w.user_id = ? AND b.start_time < ? AND (b.end_time > ? OR b.end_time = ?)
+	ORDER BY b.start_time ASC;`
+	rows, err := db.QueryContext(ctx, query, userId, to, from, time.Time{})
+	if err != nil {
+		return nil, queryError(ctx, logger, "Query QueryBreaks", err)
+	}
+	defer rows.Close()
+
+	breaks := make([]util.Break, 0, 10)
+	for rows.Next() {
+		brk, err := scanBreak(rows)
+		if err != nil {
+			return nil, queryError(ctx, logger, "Scan QueryBreaks", err)
+		}
+		breaks = append(breaks, brk)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, queryError(ctx, logger, "Rows QueryBreaks", err)
+	}
+
+	return breaks, nil
+}
+
+func (db *SQLiteDB) EndBreak(ctx context.Context, logger *slog.Logger, brk util.Break) error {
+	query := "UPDATE breaks SET end_time = ?, duration = ? WHERE id = ? AND end_time = ?;"
+
+	result, err := db.ExecContext(ctx, query, brk.EndTime, brk.Duration, brk.Id, time.Time{})
+	if err != nil {
+		return queryError(ctx, logger, "Exec EndBreak", err)
+	}
+
+	n, err := result.RowsAffected()
+	if err != nil {
+		logger.LogAttrs(ctx, slog.LevelWarn, "RowsAffected EndBreak", slog.String("err", err.Error()))
+	}
+
+	if n != 1 {
+		logger.LogAttrs(ctx, slog.LevelWarn, "RowsAffected EndBreak", slog.String("err", "There were no rows affected"))
+		return util.ErrBreakNotFound
+	}
+
+	return nil
+}
@@ -2,125 +2,153 @@ package sqlite
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"log/slog"
 
 	"github.com/NerdBow/Grinders-API/internal/util"
 )
 
-func (db *SQLiteDB) AddCategory(logger *slog.Logger, name string, userId uint64) error {
+func (db *SQLiteDB) AddCategory(ctx context.Context, logger *slog.Logger, name string, userId uint64) error {
 	query := "INSERT INTO categories (name, user_id) VALUES (?, ?);"
-	result, err := db.Exec(query, name, userId)
+	result, err := db.ExecContext(ctx, query, name, userId)
+	if isUniqueViolation(err) {
+		return util.ErrCategoryExists
+	}
 	if err != nil {
-		logger.LogAttrs(context.Background(), slog.LevelError, "Exec AddCategory", slog.String("err", err.Error()))
-		return util.ErrDatabase
+		return queryError(ctx, logger, "Exec AddCategory", err)
 	}
 
 	n, err := result.RowsAffected()
 	if err != nil {
-		logger.LogAttrs(context.Background(), slog.LevelWarn, "RowsAffected AddCategory", slog.String("err", err.Error()))
+		logger.LogAttrs(ctx, slog.LevelWarn, "RowsAffected AddCategory", slog.String("err", err.Error()))
 	}
 
 	if n != 1 {
-		logger.LogAttrs(context.Background(), slog.LevelWarn, "RowsAffected AddCategory", slog.String("err", "There were no rows affected"))
+		logger.LogAttrs(ctx, slog.LevelWarn, "RowsAffected AddCategory", slog.String("err", "There were no rows affected"))
 	}
 
 	return nil
 }
 
-func (db *SQLiteDB) GetCategory(logger *slog.Logger, name string, userId uint64) (util.Category, error) {
+func (db *SQLiteDB) GetCategory(ctx context.Context, logger *slog.Logger, name string, userId uint64) (util.Category, error) {
 	query := "SELECT id, name, user_id FROM categories WHERE user_id=? AND name=?;"
-	row := db.QueryRow(query, userId, name)
+	row := db.QueryRowContext(ctx, query, userId, name)
+
+	category := util.Category{}
+	err := row.Scan(&category.Id, &category.Name, &category.UserId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return category, util.ErrCategoryNotFound
+	}
+	if err != nil {
+		return category, queryError(ctx, logger, "Scan GetCategory", err)
+	}
+	return category, nil
+}
+
+func (db *SQLiteDB) GetCategoryById(ctx context.Context, logger *slog.Logger, categoryId uint64, userId uint64) (util.Category, error) {
+	query := "SELECT id, name, user_id FROM categories WHERE user_id=? AND id=?;"
+	row := db.QueryRowContext(ctx, query, userId, categoryId)
 
 	category := util.Category{}
 	err := row.Scan(&category.Id, &category.Name, &category.UserId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return category, util.ErrCategoryNotFound
+	}
 	if err != nil {
-		logger.LogAttrs(context.Background(), slog.LevelError, "Scan GetCategory", slog.String("err", err.Error()))
-		return category, err
+		return category, queryError(ctx, logger, "Scan GetCategoryById", err)
 	}
 	return category, nil
 }
 
-func (db *SQLiteDB) QueryCategory(logger *slog.Logger, prefix string, userId uint64) ([]util.Category, error) {
-	query := "SELECT id, name, user_id FROM categories WHERE user_id=? AND name LIKE ?;"
-	rows, err := db.Query(query, userId, prefix+"%")
+func (db *SQLiteDB) QueryCategory(ctx context.Context, logger *slog.Logger, prefix string, userId uint64) ([]util.Category, error) {
+	query := "SELECT id, name, user_id FROM categories WHERE user_id=? AND name LIKE ? ORDER BY name ASC;"
+	rows, err := db.QueryContext(ctx, query, userId, prefix+"%")
 	if err != nil {
-		logger.LogAttrs(context.Background(), slog.LevelError, "Query QueryCategory", slog.String("err", err.Error()))
-		return nil, err
+		return nil, queryError(ctx, logger, "Query QueryCategory", err)
 	}
+	defer rows.Close()
 
 	categories := make([]util.Category, 0, 10)
 	for rows.Next() {
 		category := util.Category{}
 		err = rows.Scan(&category.Id, &category.Name, &category.UserId)
 		if err != nil {
-			logger.LogAttrs(context.Background(), slog.LevelError, "Scan QueryCategory", slog.String("err", err.Error()))
-			return nil, err
+			return nil, queryError(ctx, logger, "Scan QueryCategory", err)
 		}
 		categories = append(categories, category)
 	}
+	if err = rows.Err(); err != nil {
+		return nil, queryError(ctx, logger, "Rows QueryCategory", err)
+	}
 
 	return categories, nil
 }
 
-func (db *SQLiteDB) GetUserCategories(logger *slog.Logger, userId uint64) ([]util.Category, error) {
+func (db *SQLiteDB) GetUserCategories(ctx context.Context, logger *slog.Logger, userId uint64) ([]util.Category, error) {
 	query := "SELECT id, name, user_id FROM categories WHERE user_id=? ORDER BY name ASC;"
-	rows, err := db.Query(query, userId)
+	rows, err := db.QueryContext(ctx, query, userId)
 	if err != nil {
-		logger.LogAttrs(context.Background(), slog.LevelError, "Query GetUserCategories", slog.String("err", err.Error()))
-		return nil, util.ErrDatabase
+		return nil, queryError(ctx, logger, "Query GetUserCategories", err)
 	}
+	defer rows.Close()
 
 	categories := make([]util.Category, 0, 10)
 	for rows.Next() {
 		category := util.Category{}
 		err = rows.Scan(&category.Id, &category.Name, &category.UserId)
 		if err != nil {
-			logger.LogAttrs(context.Background(), slog.LevelError, "Scan GetUserCategories", slog.String("err", err.Error()))
-			return nil, util.ErrDatabase
+			return nil, queryError(ctx, logger, "Scan GetUserCategories", err)
 		}
 		categories = append(categories, category)
 	}
+	if err = rows.Err(); err != nil {
+		return nil, queryError(ctx, logger, "Rows GetUserCategories", err)
+	}
 
 	return categories, nil
 }
 
-func (db *SQLiteDB) EditCategoryName(logger *slog.Logger, categoryId uint64, newName string, userId uint64) error {
+func (db *SQLiteDB) EditCategoryName(ctx context.Context, logger *slog.Logger, categoryId uint64, newName string, userId uint64) error {
 	query := "UPDATE categories SET name = ? WHERE user_id = ? AND id = ?;"
 
-	result, err := db.Exec(query, newName, userId, categoryId)
+	result, err := db.ExecContext(ctx, query, newName, userId, categoryId)
+	if isUniqueViolation(err) {
+		return util.ErrCategoryExists
+	}
 	if err != nil {
-		logger.LogAttrs(context.Background(), slog.LevelError, "Exec EditCategoryName", slog.String("err", err.Error()))
-		return util.ErrDatabase
+		return queryError(ctx, logger, "Exec EditCategoryName", err)
 	}
 
 	n, err := result.RowsAffected()
 	if err != nil {
-		logger.LogAttrs(context.Background(), slog.LevelWarn, "RowsAffected EditCategoryName", slog.String("err", err.Error()))
+		logger.LogAttrs(ctx, slog.LevelWarn, "RowsAffected EditCategoryName", slog.String("err", err.Error()))
 	}
 
 	if n != 1 {
-		logger.LogAttrs(context.Background(), slog.LevelWarn, "RowsAffected EditCategoryName", slog.String("err", "There were no rows affected"))
+		logger.LogAttrs(ctx, slog.LevelWarn, "RowsAffected EditCategoryName", slog.String("err", "There were no rows affected"))
+		return util.ErrCategoryNotFound
 	}
 
 	return nil
 }
 
-func (db *SQLiteDB) DeleteCategory(logger *slog.Logger, categoryId uint64, userId uint64) error {
+func (db *SQLiteDB) DeleteCategory(ctx context.Context, logger *slog.Logger, categoryId uint64, userId uint64) error {
 	query := "DELETE FROM categories WHERE user_id = ? AND id = ?;"
 
-	result, err := db.Exec(query, userId, categoryId)
+	result, err := db.ExecContext(ctx, query, userId, categoryId)
 	if err != nil {
-		logger.LogAttrs(context.Background(), slog.LevelError, "Exec DeleteCategory", slog.String("err", err.Error()))
-		return util.ErrDatabase
+		return queryError(ctx, logger, "Exec DeleteCategory", err)
 	}
 
 	n, err := result.RowsAffected()
 	if err != nil {
-		logger.LogAttrs(context.Background(), slog.LevelWarn, "RowsAffected DeleteCategory", slog.String("err", err.Error()))
+		logger.LogAttrs(ctx, slog.LevelWarn, "RowsAffected DeleteCategory", slog.String("err", err.Error()))
 	}
 
 	if n != 1 {
-		logger.LogAttrs(context.Background(), slog.LevelWarn, "RowsAffected DeleteCategory", slog.String("err", "There were no rows affected"))
+		logger.LogAttrs(ctx, slog.LevelWarn, "RowsAffected DeleteCategory", slog.String("err", "There were no rows affected"))
+		return util.ErrCategoryNotFound
 	}
 
 	return nil
@@ -3,23 +3,28 @@ package sqlite
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"log/slog"
+	"strings"
 
 	"github.com/NerdBow/Grinders-API/internal/util"
-	_ "github.com/mattn/go-sqlite3"
+	"github.com/mattn/go-sqlite3"
 )
 
 const (
 	PAGE_SIZE = 20
 )
 
+// TABLES are the tables CreateTables creates. CheckSchema uses them to tell if the schema is present.
+var TABLES = []string{"sessions", "users", "categories", "tasks", "login_attempts", "work_logs", "pauses", "breaks", "pomodoro_settings", "pomodoros", "heartbeats", "user_settings", "timer_limits", "work_log_reviews", "goals", "streaks", "rest_days", "groups", "group_members", "group_invites", "group_categories", "leaderboard_opt_outs"}
+
 type SQLiteDB struct {
 	*sql.DB
 }
 
 func NewSQLiteDB(file string) (SQLiteDB, error) {
-	db, err := sql.Open("sqlite3", fmt.Sprintf("file:%s?_journal=WAL", file))
+	db, err := sql.Open("sqlite3", fmt.Sprintf("file:%s?_journal=WAL&_txlock=immediate", file))
 	if err != nil {
 		slog.LogAttrs(context.Background(), slog.LevelError, "SQLiteDB Open", slog.String("err", err.Error()))
 		return SQLiteDB{}, err
@@ -68,11 +73,301 @@ CREATE TABLE IF NOT EXISTS "tasks" (
 	FOREIGN KEY ("category_id") REFERENCES "category"("id")
 	ON UPDATE NO ACTION ON DELETE NO ACTION
 );
+CREATE TABLE IF NOT EXISTS "work_logs" (
+	"id" INTEGER NOT NULL UNIQUE,
+	"task_id" INTEGER NOT NULL,
+	"objective" TEXT NOT NULL,
+	"work_description" TEXT NOT NULL,
+	"is_complete" BOOLEAN NOT NULL,
+	"start_time" TIMESTAMP NOT NULL,
+	"duration" INTEGER NOT NULL,
+	"end_time" TIMESTAMP NOT NULL,
+	"user_id" INTEGER NOT NULL,
+	PRIMARY KEY("id"),
+	FOREIGN KEY ("task_id") REFERENCES "tasks"("id")
+	ON UPDATE NO ACTION ON DELETE NO ACTION,
+	FOREIGN KEY ("user_id") REFERENCES "users"("id")
+	ON UPDATE NO ACTION ON DELETE NO ACTION
+);
+CREATE INDEX IF NOT EXISTS "work_logs_user_start" ON "work_logs" ("user_id", "start_time");
+CREATE UNIQUE INDEX IF NOT EXISTS "work_logs_one_running" ON "work_logs" ("user_id") WHERE "is_complete" = 0;
+CREATE TABLE IF NOT EXISTS "pauses" (
+	"id" INTEGER NOT NULL UNIQUE,
+	"start_time" TIMESTAMP NOT NULL,
+	"duration" INTEGER NOT NULL,
+	"end_time" TIMESTAMP NOT NULL,
+	"work_log_id" INTEGER NOT NULL,
+	PRIMARY KEY("id"),
+	FOREIGN KEY ("work_log_id") REFERENCES "work_logs"("id")
+	ON UPDATE NO ACTION ON DELETE NO ACTION
+);
+CREATE INDEX IF NOT EXISTS "pauses_work_log" ON "pauses" ("work_log_id");
+CREATE UNIQUE INDEX IF NOT EXISTS "pauses_one_running" ON "pauses" ("work_log_id") WHERE "end_time" = '0001-01-01 00:00:00+00:00';
+CREATE TABLE IF NOT EXISTS "breaks" (
+	"id" INTEGER NOT NULL UNIQUE,
+	"start_time" TIMESTAMP NOT NULL,
+	"duration" INTEGER NOT NULL,
+	"end_time" TIMESTAMP NOT NULL,
+	"work_log_id" INTEGER NOT NULL,
+	"user_id" INTEGER NOT NULL,
+	PRIMARY KEY("id"),
+	FOREIGN KEY ("work_log_id") REFERENCES "work_logs"("id")
+	ON UPDATE NO ACTION ON DELETE NO ACTION,
+	FOREIGN KEY ("user_id") REFERENCES "users"("id")
+	ON UPDATE NO ACTION ON DELETE NO ACTION
+);
+CREATE INDEX IF NOT EXISTS "breaks_work_log" ON "breaks" ("work_log_id");
+CREATE UNIQUE INDEX IF NOT EXISTS "breaks_one_running" ON "breaks" ("user_id") WHERE "end_time" = '0001-01-01 00:00:00+00:00';
+CREATE TABLE IF NOT EXISTS "pomodoro_settings" (
+	"user_id" INTEGER NOT NULL UNIQUE,
+	"focus_length" INTEGER NOT NULL,
+	"short_break_length" INTEGER NOT NULL,
+	"long_break_length" INTEGER NOT NULL,
+	"cycles_before_long_break" INTEGER NOT NULL,
+	PRIMARY KEY("user_id"),
+	FOREIGN KEY ("user_id") REFERENCES "users"("id")
+	ON UPDATE NO ACTION ON DELETE NO ACTION
+);
+CREATE TABLE IF NOT EXISTS "pomodoros" (
+	"user_id" INTEGER NOT NULL UNIQUE,
+	"phase" TEXT NOT NULL,
+	"cycle" INTEGER NOT NULL,
+	"task_id" INTEGER NOT NULL,
+	"objective" TEXT NOT NULL,
+	"phase_start" TIMESTAMP NOT NULL,
+	"phase_end" TIMESTAMP NOT NULL,
+	"work_log_id" INTEGER NOT NULL,
+	"break_id" INTEGER NOT NULL,
+	PRIMARY KEY("user_id"),
+	FOREIGN KEY ("user_id") REFERENCES "users"("id")
+	ON UPDATE NO ACTION ON DELETE NO ACTION
+);
+CREATE INDEX IF NOT EXISTS "pomodoros_phase_end" ON "pomodoros" ("phase_end");
+CREATE TABLE IF NOT EXISTS "heartbeats" (
+	"user_id" INTEGER NOT NULL UNIQUE,
+	"last_heartbeat" TIMESTAMP NOT NULL,
+	"idle_threshold" INTEGER NOT NULL,
+	"idle_time" TIMESTAMP NOT NULL,
+	PRIMARY KEY("user_id"),
+	FOREIGN KEY ("user_id") REFERENCES "users"("id")
+	ON UPDATE NO ACTION ON DELETE NO ACTION
+);
+CREATE INDEX IF NOT EXISTS "heartbeats_idle_time" ON "heartbeats" ("idle_time");
+CREATE TABLE IF NOT EXISTS "user_settings" (
+	"user_id" INTEGER NOT NULL UNIQUE,
+	"time_zone" TEXT NOT NULL,
+	PRIMARY KEY("user_id"),
+	FOREIGN KEY ("user_id") REFERENCES "users"("id")
+	ON UPDATE NO ACTION ON DELETE NO ACTION
+);
+CREATE TABLE IF NOT EXISTS "timer_limits" (
+	"user_id" INTEGER NOT NULL UNIQUE,
+	"max_duration" INTEGER NOT NULL,
+	"cutoff_time" TEXT NOT NULL,
+	PRIMARY KEY("user_id"),
+	FOREIGN KEY ("user_id") REFERENCES "users"("id")
+	ON UPDATE NO ACTION ON DELETE NO ACTION
+);
+CREATE TABLE IF NOT EXISTS "work_log_reviews" (
+	"work_log_id" INTEGER NOT NULL UNIQUE,
+	"reason" TEXT NOT NULL,
+	"auto_stop_time" TIMESTAMP NOT NULL,
+	PRIMARY KEY("work_log_id"),
+	FOREIGN KEY ("work_log_id") REFERENCES "work_logs"("id")
+	ON UPDATE NO ACTION ON DELETE NO ACTION
+);
+CREATE TABLE IF NOT EXISTS "goals" (
+	"id" INTEGER NOT NULL UNIQUE,
+	"category_id" INTEGER NOT NULL,
+	"value" INTEGER NOT NULL,
+	"type" INTEGER NOT NULL,
+	PRIMARY KEY("id"),
+	FOREIGN KEY ("category_id") REFERENCES "categories"("id")
+	ON UPDATE NO ACTION ON DELETE NO ACTION
+);
+CREATE TABLE IF NOT EXISTS "streaks" (
+	"user_id" INTEGER NOT NULL,
+	"goal_id" INTEGER NOT NULL,
+	"current" INTEGER NOT NULL,
+	"longest" INTEGER NOT NULL,
+	"checked_until" TIMESTAMP NOT NULL,
+	PRIMARY KEY("user_id", "goal_id"),
+	FOREIGN KEY ("user_id") REFERENCES "users"("id")
+	ON UPDATE NO ACTION ON DELETE NO ACTION
+);
+CREATE TABLE IF NOT EXISTS "rest_days" (
+	"user_id" INTEGER NOT NULL,
+	"date" TEXT NOT NULL,
+	PRIMARY KEY("user_id", "date"),
+	FOREIGN KEY ("user_id") REFERENCES "users"("id")
+	ON UPDATE NO ACTION ON DELETE NO ACTION
+);
+CREATE TABLE IF NOT EXISTS "groups" (
+	"id" INTEGER NOT NULL UNIQUE,
+	"name" TEXT NOT NULL,
+	"owner_id" INTEGER NOT NULL,
+	PRIMARY KEY("id"),
+	FOREIGN KEY ("owner_id") REFERENCES "users"("id")
+	ON UPDATE NO ACTION ON DELETE NO ACTION
+);
+CREATE TABLE IF NOT EXISTS "group_members" (
+	"id" INTEGER NOT NULL UNIQUE,
+	"group_id" INTEGER NOT NULL,
+	"user_id" INTEGER NOT NULL,
+	"role" INTEGER NOT NULL,
+	PRIMARY KEY("id"),
+	UNIQUE("group_id", "user_id"),
+	FOREIGN KEY ("group_id") REFERENCES "groups"("id")
+	ON UPDATE NO ACTION ON DELETE NO ACTION,
+	FOREIGN KEY ("user_id") REFERENCES "users"("id")
+	ON UPDATE NO ACTION ON DELETE NO ACTION
+);
+CREATE INDEX IF NOT EXISTS "group_members_user" ON "group_members" ("user_id");
+CREATE TABLE IF NOT EXISTS "group_invites" (
+	"id" INTEGER NOT NULL UNIQUE,
+	"group_id" INTEGER NOT NULL,
+	"hashed_code" TEXT NOT NULL UNIQUE,
+	"max_uses" INTEGER NOT NULL,
+	"uses" INTEGER NOT NULL,
+	"expiration_time" TIMESTAMP NOT NULL,
+	"creation_time" TIMESTAMP NOT NULL,
+	PRIMARY KEY("id"),
+	FOREIGN KEY ("group_id") REFERENCES "groups"("id")
+	ON UPDATE NO ACTION ON DELETE NO ACTION
+);
+CREATE INDEX IF NOT EXISTS "group_invites_group" ON "group_invites" ("group_id");
+CREATE TABLE IF NOT EXISTS "group_categories" (
+	"id" INTEGER NOT NULL UNIQUE,
+	"name" TEXT NOT NULL,
+	"group_id" INTEGER NOT NULL,
+	PRIMARY KEY("id"),
+	FOREIGN KEY ("group_id") REFERENCES "groups"("id")
+	ON UPDATE NO ACTION ON DELETE NO ACTION
+);
+CREATE TABLE IF NOT EXISTS "leaderboard_opt_outs" (
+	"user_id" INTEGER NOT NULL UNIQUE,
+	PRIMARY KEY("user_id"),
+	FOREIGN KEY ("user_id") REFERENCES "users"("id")
+	ON UPDATE NO ACTION ON DELETE NO ACTION
+);
+CREATE INDEX IF NOT EXISTS "tasks_user_completion" ON "tasks" ("user_id", "completion_time");
+CREATE TABLE IF NOT EXISTS "login_attempts" (
+	"key" TEXT NOT NULL UNIQUE,
+	"failures" INTEGER NOT NULL,
+	"last_failure" TIMESTAMP NOT NULL,
+	"locked_until" TIMESTAMP NOT NULL,
+	PRIMARY KEY("key")
+);
+CREATE INDEX IF NOT EXISTS "login_attempts_last_failure" ON "login_attempts" ("last_failure");
 `
 	_, err := db.Exec(query)
 	if err != nil {
 		slog.LogAttrs(context.Background(), slog.LevelError, "SQLiteDB Create Table", slog.String("err", err.Error()))
 		return util.ErrDatabase
 	}
+
+	for _, index := range INDEXES {
+		if err := db.addIndex(index); err != nil {
+			slog.LogAttrs(context.Background(), slog.LevelError, "SQLiteDB Add Index", slog.String("index", index.name), slog.String("err", err.Error()))
+			return util.ErrDatabase
+		}
+	}
+	return nil
+}
+
+// index is a unique index added after its table was first released.
+// Older databases can hold rows that violate it. They are not changed, the API refuses to start until they are fixed by hand.
+type index struct {
+	name       string
+	definition string
+	conflicts  string // Describes each group of rows that violates the index, one per row of the result
+}
+
+// INDEXES are the unique indexes CreateTables adds to older databases, in the order they were introduced.
+var INDEXES = []index{
+	{
+		name:       "categories_user_name",
+		definition: `CREATE UNIQUE INDEX IF NOT EXISTS "categories_user_name" ON "categories" ("user_id", "name");`,
+		conflicts: `SELECT 'user ' || user_id || ' has the categories ' || GROUP_CONCAT(id, ', ') || ' named "' || name || '"'
+	FROM categories GROUP BY user_id, name HAVING COUNT(*) > 1;`,
+	},
+}
+
+// addIndex creates the index if the database does not have it yet.
+// If rows violate the index, then an error listing them is returned.
+func (db *SQLiteDB) addIndex(index index) error {
+	count := 0
+	err := db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE type = 'index' AND name = ?;", index.name).Scan(&count)
+	if err != nil || count > 0 {
+		return err
+	}
+
+	rows, err := db.Query(index.conflicts)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	conflicts := make([]string, 0)
+	for rows.Next() {
+		conflict := ""
+		if err := rows.Scan(&conflict); err != nil {
+			return err
+		}
+		conflicts = append(conflicts, conflict)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	if len(conflicts) > 0 {
+		return fmt.Errorf("rename or delete the rows that violate the index, then restart: %s", strings.Join(conflicts, "; "))
+	}
+
+	_, err = db.Exec(index.definition)
+	return err
+}
+
+// CheckSchema checks that all of TABLES exist in the database.
+// If any are missing ErrSchemaMissing is returned.
+func (db *SQLiteDB) CheckSchema(ctx context.Context, logger *slog.Logger) error {
+	query := "SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name IN (?" + strings.Repeat(", ?", len(TABLES)-1) + ");"
+	params := make([]any, 0, len(TABLES))
+	for _, table := range TABLES {
+		params = append(params, table)
+	}
+
+	n := 0
+	err := db.QueryRowContext(ctx, query, params...).Scan(&n)
+	if err != nil {
+		return queryError(ctx, logger, "Scan CheckSchema", err)
+	}
+
+	if n != len(TABLES) {
+		logger.LogAttrs(ctx, slog.LevelWarn, "CheckSchema", slog.Int("found", n), slog.Int("expected", len(TABLES)))
+		return util.ErrSchemaMissing
+	}
+
 	return nil
 }
+
+// isUniqueViolation reports whether err was caused by a UNIQUE constraint or index.
+func isUniqueViolation(err error) bool {
+	sqliteErr := sqlite3.Error{}
+	return errors.As(err, &sqliteErr) && sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique
+}
+
+// queryError logs err and converts it into the error returned by SQLiteDB methods.
+// Queries that were aborted because the request was cancelled or ran out of time are reported as such instead of ErrDatabase.
+func queryError(ctx context.Context, logger *slog.Logger, msg string, err error) error {
+	switch {
+	case errors.Is(err, context.Canceled):
+		logger.LogAttrs(ctx, slog.LevelInfo, msg, slog.String("err", err.Error()))
+		return util.ErrRequestCanceled
+	case errors.Is(err, context.DeadlineExceeded):
+		logger.LogAttrs(ctx, slog.LevelWarn, msg, slog.String("err", err.Error()))
+		return util.ErrRequestTimeout
+	default:
+		logger.LogAttrs(ctx, slog.LevelError, msg, slog.String("err", err.Error()))
+		return util.ErrDatabase
+	}
+}
@@ -0,0 +1,208 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/NerdBow/Grinders-API/internal/util"
+)
+
+const goalColumns = "g.id, g.category_id, g.value, g.type"
+
+func scanGoal(row scanner) (util.Goal, error) {
+	goal := util.Goal{}
+	err := row.Scan(&goal.Id, &goal.CategoryId, &goal.Value, &goal.Type)
+	return goal, err
+}
+
+func (db *SQLiteDB) AddGoal(ctx context.Context, logger *slog.Logger, goal util.Goal) (uint64, error) {
+	query := "INSERT INTO goals (category_id, value, type) VALUES (?, ?, ?);"
+
+	result, err := db.ExecContext(ctx, query, goal.CategoryId, goal.Value, goal.Type)
+	if err != nil {
+		return 0, queryError(ctx, logger, "Exec AddGoal", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, queryError(ctx, logger, "LastInsertId AddGoal", err)
+	}
+
+	return uint64(id), nil
+}
+
+func (db *SQLiteDB) GetGoal(ctx context.Context, logger *slog.Logger, goalId uint64, userId uint64) (util.Goal, error) {
+	query := "SELECT " + goalColumns + " FROM goals g INNER JOIN categories c ON g.category_id = c.id WHERE g.id = ? AND c.user_id = ?;"
+	row := db.QueryRowContext(ctx, query, goalId, userId)
+
+	goal, err := scanGoal(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return goal, util.ErrGoalNotFound
+	}
+	if err != nil {
+		return goal, queryError(ctx, logger, "Scan GetGoal", err)
+	}
+	return goal, nil
+}
+
+func (db *SQLiteDB) GetUserGoals(ctx context.Context, logger *slog.Logger, userId uint64) ([]util.Goal, error) {
+	query := "SELECT " + goalColumns + " FROM goals g INNER JOIN categories c ON g.category_id = c.id WHERE c.user_id = ? ORDER BY g.id ASC;"
+	rows, err := db.QueryContext(ctx, query, userId)
+	if err != nil {
+		return nil, queryError(ctx, logger, "Query GetUserGoals", err)
+	}
+	defer rows.Close()
+
+	goals := make([]util.Goal, 0, 10)
+	for rows.Next() {
+		goal, err := scanGoal(rows)
+		if err != nil {
+			return nil, queryError(ctx, logger, "Scan GetUserGoals", err)
+		}
+		goals = append(goals, goal)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, queryError(ctx, logger, "Rows GetUserGoals", err)
+	}
+
+	return goals, nil
+}
+
+func (db *SQLiteDB) EditGoal(ctx context.Context, logger *slog.Logger, goal util.Goal, userId uint64) error {
+	query := `UPDATE goals SET category_id = ?, value = ?, type = ?
+	WHERE id = ? AND category_id IN (SELECT id FROM categories WHERE user_id = ?);`
+
+	result, err := db.ExecContext(ctx, query, goal.CategoryId, goal.Value, goal.Type, goal.Id, userId)
+	if err != nil {
+		return queryError(ctx, logger, "Exec EditGoal", err)
+	}
+
+	n, err := result.RowsAffected()
+	if err != nil {
+		logger.LogAttrs(ctx, slog.LevelWarn, "RowsAffected EditGoal", slog.String("err", err.Error()))
+	}
+
+	if n != 1 {
+		logger.LogAttrs(ctx, slog.LevelWarn, "RowsAffected EditGoal", slog.String("err", "There were no rows affected"))
+		return util.ErrGoalNotFound
+	}
+
+	return nil
+}
+
+func (db *SQLiteDB) DeleteGoal(ctx context.Context, logger *slog.Logger, goalId uint64, userId uint64) error {
+	query := "DELETE FROM goals WHERE id = ? AND category_id IN (SELECT id FROM categories WHERE user_id = ?);"
+
+	result, err := db.ExecContext(ctx, query, goalId, userId)
+	if err != nil {
+		return queryError(ctx, logger, "Exec DeleteGoal", err)
+	}
+
+	n, err := result.RowsAffected()
+	if err != nil {
+		logger.LogAttrs(ctx, slog.LevelWarn, "RowsAffected DeleteGoal", slog.String("err", err.Error()))
+	}
+
+	if n != 1 {
+		logger.LogAttrs(ctx, slog.LevelWarn, "RowsAffected DeleteGoal", slog.String("err", "There were no rows affected"))
+		return util.ErrGoalNotFound
+	}
+
+	return nil
+}
+
+func (db *SQLiteDB) GetCategoryWorkLogs(ctx context.Context, logger *slog.Logger, userId uint64, categoryId uint64, from time.Time, to time.Time) ([]util.WorkLog, error) {
+	query := `SELECT w.id, w.task_id, w.objective, w.work_description, w.is_complete, w.start_time, w.duration, w.end_time, w.user_id
+	FROM work_logs w INNER JOIN tasks t ON w.task_id = t.id
+	WHERE w.user_id = ? AND t.category_id = ? AND w.start_time < ? AND (w.end_time > ? OR w.is_complete = ?)
+	ORDER BY w.start_time ASC;`
+	rows, err := db.QueryContext(ctx, query, userId, categoryId, to, from, false)
+	if err != nil {
+		return nil, queryError(ctx, logger, "Query GetCategoryWorkLogs", err)
+	}
+	defer rows.Close()
+
+	workLogs := make([]util.WorkLog, 0, 10)
+	for rows.Next() {
+		workLog, err := scanWorkLog(rows)
+		if err != nil {
+			return nil, queryError(ctx, logger, "Scan GetCategoryWorkLogs", err)
+		}
+		workLogs = append(workLogs, workLog)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, queryError(ctx, logger, "Rows GetCategoryWorkLogs", err)
+	}
+
+	return workLogs, nil
+}
+
+func (db *SQLiteDB) CountCompletedTasks(ctx context.Context, logger *slog.Logger, userId uint64, categoryId uint64, from time.Time, to time.Time) (int64, error) {
+	query := `SELECT COUNT(*) FROM tasks
+	WHERE user_id = ? AND category_id = ? AND is_completed = ? AND completion_time >= ? AND completion_time < ?;`
+	row := db.QueryRowContext(ctx, query, userId, categoryId, true, from, to)
+
+	count := int64(0)
+	if err := row.Scan(&count); err != nil {
+		return 0, queryError(ctx, logger, "Scan CountCompletedTasks", err)
+	}
+	return count, nil
+}
+
+func (db *SQLiteDB) GetDueTasks(ctx context.Context, logger *slog.Logger, userId uint64, categoryId uint64, from time.Time, to time.Time) ([]util.Task, error) {
+	query := `SELECT id, name, creation_time, completion_time, deadline_time, is_completed, category_id, user_id FROM tasks
+	WHERE user_id = ? AND category_id = ? AND deadline_time >= ? AND deadline_time < ?
+	ORDER BY deadline_time ASC;`
+	rows, err := db.QueryContext(ctx, query, userId, categoryId, from, to)
+	if err != nil {
+		return nil, queryError(ctx, logger, "Query GetDueTasks", err)
+	}
+	defer rows.Close()
+
+	tasks := make([]util.Task, 0, 10)
+	for rows.Next() {
+		task := util.Task{}
+		err := rows.Scan(&task.Id, &task.Name, &task.CreationTime, &task.CompletionTime, &task.DeadlineTime, &task.IsComplete, &task.CategoryId, &task.UserId)
+		if err != nil {
+			return nil, queryError(ctx, logger, "Scan GetDueTasks", err)
+		}
+		tasks = append(tasks, task)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, queryError(ctx, logger, "Rows GetDueTasks", err)
+	}
+
+	return tasks, nil
+}
+
+func (db *SQLiteDB) GetCategoryStart(ctx context.Context, logger *slog.Logger, userId uint64, categoryId uint64) (time.Time, error) {
+	query := "SELECT creation_time FROM tasks WHERE user_id = ? AND category_id = ? ORDER BY creation_time ASC LIMIT 1;"
+	row := db.QueryRowContext(ctx, query, userId, categoryId)
+
+	start := time.Time{}
+	err := row.Scan(&start)
+	if errors.Is(err, sql.ErrNoRows) {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, queryError(ctx, logger, "Scan GetCategoryStart Tasks", err)
+	}
+
+	query = `SELECT w.start_time FROM work_logs w INNER JOIN tasks t ON w.task_id = t.id
+	WHERE w.user_id = ? AND t.category_id = ? ORDER BY w.start_time ASC LIMIT 1;`
+	row = db.QueryRowContext(ctx, query, userId, categoryId)
+
+	workLogStart := time.Time{}
+	err = row.Scan(&workLogStart)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return time.Time{}, queryError(ctx, logger, "Scan GetCategoryStart WorkLogs", err)
+	}
+	if err == nil && workLogStart.Before(start) {
+		start = workLogStart
+	}
+
+	return start, nil
+}
@@ -0,0 +1,89 @@
+package sqlite
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/NerdBow/Grinders-API/internal/util"
+)
+
+func (db *SQLiteDB) AddGroupCategory(ctx context.Context, logger *slog.Logger, category util.GroupCategory) (uint64, error) {
+	query := "INSERT INTO group_categories (name, group_id) VALUES (?, ?);"
+
+	result, err := db.ExecContext(ctx, query, category.Name, category.GroupId)
+	if err != nil {
+		return 0, queryError(ctx, logger, "Exec AddGroupCategory", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, queryError(ctx, logger, "LastInsertId AddGroupCategory", err)
+	}
+
+	return uint64(id), nil
+}
+
+func (db *SQLiteDB) GetGroupCategories(ctx context.Context, logger *slog.Logger, groupId uint64) ([]util.GroupCategory, error) {
+	query := "SELECT id, name, group_id FROM group_categories WHERE group_id = ? ORDER BY name ASC;"
+	rows, err := db.QueryContext(ctx, query, groupId)
+	if err != nil {
+		return nil, queryError(ctx, logger, "Query GetGroupCategories", err)
+	}
+	defer rows.Close()
+
+	categories := make([]util.GroupCategory, 0, 10)
+	for rows.Next() {
+		category := util.GroupCategory{}
+		if err := rows.Scan(&category.Id, &category.Name, &category.GroupId); err != nil {
+			return nil, queryError(ctx, logger, "Scan GetGroupCategories", err)
+		}
+		categories = append(categories, category)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, queryError(ctx, logger, "Rows GetGroupCategories", err)
+	}
+
+	return categories, nil
+}
+
+func (db *SQLiteDB) EditGroupCategoryName(ctx context.Context, logger *slog.Logger, categoryId uint64, newName string, groupId uint64) error {
+	query := "UPDATE group_categories SET name = ? WHERE id = ? AND group_id = ?;"
+
+	result, err := db.ExecContext(ctx, query, newName, categoryId, groupId)
+	if err != nil {
+		return queryError(ctx, logger, "Exec EditGroupCategoryName", err)
+	}
+
+	n, err := result.RowsAffected()
+	if err != nil {
+		logger.LogAttrs(ctx, slog.LevelWarn, "RowsAffected EditGroupCategoryName", slog.String("err", err.Error()))
+	}
+
+	if n != 1 {
+		logger.LogAttrs(ctx, slog.LevelWarn, "RowsAffected EditGroupCategoryName", slog.String("err", "There were no rows affected"))
+		return util.ErrGroupCategoryNotFound
+	}
+
+	return nil
+}
+
+func (db *SQLiteDB) DeleteGroupCategory(ctx context.Context, logger *slog.Logger, categoryId uint64, groupId uint64) error {
+	query := "DELETE FROM group_categories WHERE id = ? AND group_id = ?;"
+
+	result, err := db.ExecContext(ctx, query, categoryId, groupId)
+	if err != nil {
+		return queryError(ctx, logger, "Exec DeleteGroupCategory", err)
+	}
+
+	n, err := result.RowsAffected()
+	if err != nil {
+		logger.LogAttrs(ctx, slog.LevelWarn, "RowsAffected DeleteGroupCategory", slog.String("err", err.Error()))
+	}
+
+	if n != 1 {
+		logger.LogAttrs(ctx, slog.LevelWarn, "RowsAffected DeleteGroupCategory", slog.String("err", "There were no rows affected"))
+		return util.ErrGroupCategoryNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,329 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/NerdBow/Grinders-API/internal/util"
+)
+
+// The start and end of the work log w clipped to the time range from :from to :to, in seconds.
+// Running work logs end at :to, which must not be after now.
+const (
+	focusStart = "MAX(unixepoch(w.start_time, 'subsec'), unixepoch(:from, 'subsec'))"
+	focusEnd   = "unixepoch(CASE WHEN w.is_complete AND w.end_time < :to THEN w.end_time ELSE :to END, 'subsec')"
+)
+
+// focusedSeconds is the focused time of the work log w within the time range from :from to :to in whole seconds,
+// which is the clipped time of the work log without its pauses. Running pauses end with the work log.
+// It is measured like the time goal evaluators measure it, so the times agree with goal progress.
+const focusedSeconds = "CAST(ROUND(MAX(0, " + focusEnd + " - " + focusStart + ` - (
+	SELECT COALESCE(SUM(MAX(0,
+		MIN(CASE WHEN p.end_time = '0001-01-01 00:00:00+00:00' THEN ` + focusEnd + " ELSE unixepoch(p.end_time, 'subsec') END, " + focusEnd + `)
+		- MAX(unixepoch(p.start_time, 'subsec'), ` + focusStart + `))), 0)
+	FROM pauses p WHERE p.work_log_id = w.id)), 3) AS INTEGER)`
+
+// focusedWorkLog is the condition on the work log w for work logs that overlap the time range from :from to :to.
+const focusedWorkLog = "w.start_time < :to AND (w.is_complete = 0 OR w.end_time > :from)"
+
+func (db *SQLiteDB) AddGroup(ctx context.Context, logger *slog.Logger, group util.Group) (uint64, error) {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, queryError(ctx, logger, "Begin AddGroup", err)
+	}
+	defer tx.Rollback()
+
+	query := "INSERT INTO groups (name, owner_id) VALUES (?, ?);"
+	result, err := tx.ExecContext(ctx, query, group.Name, group.OwnerId)
+	if err != nil {
+		return 0, queryError(ctx, logger, "Exec AddGroup", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, queryError(ctx, logger, "LastInsertId AddGroup", err)
+	}
+
+	query = "INSERT INTO group_members (group_id, user_id, role) VALUES (?, ?, ?);"
+	_, err = tx.ExecContext(ctx, query, id, group.OwnerId, util.ROLE_OWNER)
+	if err != nil {
+		return 0, queryError(ctx, logger, "Exec AddGroup AddOwner", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return 0, queryError(ctx, logger, "Commit AddGroup", err)
+	}
+
+	return uint64(id), nil
+}
+
+func (db *SQLiteDB) GetGroup(ctx context.Context, logger *slog.Logger, groupId uint64, userId uint64) (util.Group, error) {
+	query := `SELECT g.id, g.name, g.owner_id FROM groups g INNER JOIN group_members m ON m.group_id = g.id
+	WHERE g.id = ? AND m.user_id = ?;`
+	row := db.QueryRowContext(ctx, query, groupId, userId)
+
+	group := util.Group{}
+	err := row.Scan(&group.Id, &group.Name, &group.OwnerId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return group, util.ErrGroupNotFound
+	}
+	if err != nil {
+		return group, queryError(ctx, logger, "Scan GetGroup", err)
+	}
+	return group, nil
+}
+
+func (db *SQLiteDB) GetUserGroups(ctx context.Context, logger *slog.Logger, userId uint64) ([]util.Group, error) {
+	query := `SELECT g.id, g.name, g.owner_id FROM groups g INNER JOIN group_members m ON m.group_id = g.id
+	WHERE m.user_id = ? ORDER BY g.name ASC;`
+	rows, err := db.QueryContext(ctx, query, userId)
+	if err != nil {
+		return nil, queryError(ctx, logger, "Query GetUserGroups", err)
+	}
+	defer rows.Close()
+
+	groups := make([]util.Group, 0, 5)
+	for rows.Next() {
+		group := util.Group{}
+		if err := rows.Scan(&group.Id, &group.Name, &group.OwnerId); err != nil {
+			return nil, queryError(ctx, logger, "Scan GetUserGroups", err)
+		}
+		groups = append(groups, group)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, queryError(ctx, logger, "Rows GetUserGroups", err)
+	}
+
+	return groups, nil
+}
+
+func (db *SQLiteDB) EditGroupName(ctx context.Context, logger *slog.Logger, groupId uint64, newName string, ownerId uint64) error {
+	query := "UPDATE groups SET name = ? WHERE id = ? AND owner_id = ?;"
+
+	result, err := db.ExecContext(ctx, query, newName, groupId, ownerId)
+	if err != nil {
+		return queryError(ctx, logger, "Exec EditGroupName", err)
+	}
+
+	n, err := result.RowsAffected()
+	if err != nil {
+		logger.LogAttrs(ctx, slog.LevelWarn, "RowsAffected EditGroupName", slog.String("err", err.Error()))
+	}
+
+	if n != 1 {
+		logger.LogAttrs(ctx, slog.LevelWarn, "RowsAffected EditGroupName", slog.String("err", "There were no rows affected"))
+		return util.ErrGroupNotFound
+	}
+
+	return nil
+}
+
+func (db *SQLiteDB) DeleteGroup(ctx context.Context, logger *slog.Logger, groupId uint64, ownerId uint64) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return queryError(ctx, logger, "Begin DeleteGroup", err)
+	}
+	defer tx.Rollback()
+
+	query := "DELETE FROM groups WHERE id = ? AND owner_id = ?;"
+	result, err := tx.ExecContext(ctx, query, groupId, ownerId)
+	if err != nil {
+		return queryError(ctx, logger, "Exec DeleteGroup", err)
+	}
+
+	n, err := result.RowsAffected()
+	if err != nil {
+		logger.LogAttrs(ctx, slog.LevelWarn, "RowsAffected DeleteGroup", slog.String("err", err.Error()))
+	}
+	if n != 1 {
+		logger.LogAttrs(ctx, slog.LevelWarn, "RowsAffected DeleteGroup", slog.String("err", "There were no rows affected"))
+		return util.ErrGroupNotFound
+	}
+
+	query = "DELETE FROM group_members WHERE group_id = ?;"
+	_, err = tx.ExecContext(ctx, query, groupId)
+	if err != nil {
+		return queryError(ctx, logger, "Exec DeleteGroup Members", err)
+	}
+
+	query = "DELETE FROM group_invites WHERE group_id = ?;"
+	_, err = tx.ExecContext(ctx, query, groupId)
+	if err != nil {
+		return queryError(ctx, logger, "Exec DeleteGroup Invites", err)
+	}
+
+	query = "DELETE FROM group_categories WHERE group_id = ?;"
+	_, err = tx.ExecContext(ctx, query, groupId)
+	if err != nil {
+		return queryError(ctx, logger, "Exec DeleteGroup Categories", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return queryError(ctx, logger, "Commit DeleteGroup", err)
+	}
+
+	return nil
+}
+
+func (db *SQLiteDB) TransferGroupOwnership(ctx context.Context, logger *slog.Logger, groupId uint64, ownerId uint64, newOwnerId uint64) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return queryError(ctx, logger, "Begin TransferGroupOwnership", err)
+	}
+	defer tx.Rollback()
+
+	query := "UPDATE groups SET owner_id = ? WHERE id = ? AND owner_id = ?;"
+	result, err := tx.ExecContext(ctx, query, newOwnerId, groupId, ownerId)
+	if err != nil {
+		return queryError(ctx, logger, "Exec TransferGroupOwnership", err)
+	}
+
+	n, err := result.RowsAffected()
+	if err != nil {
+		logger.LogAttrs(ctx, slog.LevelWarn, "RowsAffected TransferGroupOwnership", slog.String("err", err.Error()))
+	}
+	if n != 1 {
+		logger.LogAttrs(ctx, slog.LevelWarn, "RowsAffected TransferGroupOwnership", slog.String("err", "There were no rows affected"))
+		return util.ErrGroupNotFound
+	}
+
+	query = "UPDATE group_members SET role = ? WHERE group_id = ? AND user_id = ?;"
+	result, err = tx.ExecContext(ctx, query, util.ROLE_OWNER, groupId, newOwnerId)
+	if err != nil {
+		return queryError(ctx, logger, "Exec TransferGroupOwnership NewOwner", err)
+	}
+
+	n, err = result.RowsAffected()
+	if err != nil {
+		logger.LogAttrs(ctx, slog.LevelWarn, "RowsAffected TransferGroupOwnership NewOwner", slog.String("err", err.Error()))
+	}
+	if n != 1 {
+		return util.ErrMemberNotFound
+	}
+
+	_, err = tx.ExecContext(ctx, query, util.ROLE_ADMIN, groupId, ownerId)
+	if err != nil {
+		return queryError(ctx, logger, "Exec TransferGroupOwnership OldOwner", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return queryError(ctx, logger, "Commit TransferGroupOwnership", err)
+	}
+
+	return nil
+}
+
+func (db *SQLiteDB) AddGroupMember(ctx context.Context, logger *slog.Logger, member util.GroupMember) (uint64, error) {
+	query := "INSERT INTO group_members (group_id, user_id, role) VALUES (?, ?, ?);"
+
+	result, err := db.ExecContext(ctx, query, member.GroupId, member.UserId, member.Role)
+	if isUniqueViolation(err) {
+		return 0, util.ErrAlreadyMember
+	}
+	if err != nil {
+		return 0, queryError(ctx, logger, "Exec AddGroupMember", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, queryError(ctx, logger, "LastInsertId AddGroupMember", err)
+	}
+
+	return uint64(id), nil
+}
+
+func (db *SQLiteDB) GetGroupMembers(ctx context.Context, logger *slog.Logger, groupId uint64) ([]util.GroupMember, error) {
+	query := `SELECT m.id, m.group_id, m.user_id, u.username, m.role FROM group_members m INNER JOIN users u ON m.user_id = u.id
+	WHERE m.group_id = ? ORDER BY u.username ASC;`
+	rows, err := db.QueryContext(ctx, query, groupId)
+	if err != nil {
+		return nil, queryError(ctx, logger, "Query GetGroupMembers", err)
+	}
+	defer rows.Close()
+
+	members := make([]util.GroupMember, 0, 10)
+	for rows.Next() {
+		member := util.GroupMember{}
+		if err := rows.Scan(&member.Id, &member.GroupId, &member.UserId, &member.Username, &member.Role); err != nil {
+			return nil, queryError(ctx, logger, "Scan GetGroupMembers", err)
+		}
+		members = append(members, member)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, queryError(ctx, logger, "Rows GetGroupMembers", err)
+	}
+
+	return members, nil
+}
+
+func (db *SQLiteDB) GetGroupMember(ctx context.Context, logger *slog.Logger, groupId uint64, userId uint64) (util.GroupMember, error) {
+	query := `SELECT m.id, m.group_id, m.user_id, u.username, m.role FROM group_members m INNER JOIN users u ON m.user_id = u.id
+	WHERE m.group_id = ? AND m.user_id = ?;`
+	row := db.QueryRowContext(ctx, query, groupId, userId)
+
+	member := util.GroupMember{}
+	err := row.Scan(&member.Id, &member.GroupId, &member.UserId, &member.Username, &member.Role)
+	if errors.Is(err, sql.ErrNoRows) {
+		return member, util.ErrMemberNotFound
+	}
+	if err != nil {
+		return member, queryError(ctx, logger, "Scan GetGroupMember", err)
+	}
+	return member, nil
+}
+
+func (db *SQLiteDB) SetMemberRole(ctx context.Context, logger *slog.Logger, groupId uint64, userId uint64, role util.GroupRole) error {
+	query := "UPDATE group_members SET role = ? WHERE group_id = ? AND user_id = ?;"
+
+	result, err := db.ExecContext(ctx, query, role, groupId, userId)
+	if err != nil {
+		return queryError(ctx, logger, "Exec SetMemberRole", err)
+	}
+
+	n, err := result.RowsAffected()
+	if err != nil {
+		logger.LogAttrs(ctx, slog.LevelWarn, "RowsAffected SetMemberRole", slog.String("err", err.Error()))
+	}
+
+	if n != 1 {
+		logger.LogAttrs(ctx, slog.LevelWarn, "RowsAffected SetMemberRole", slog.String("err", "There were no rows affected"))
+		return util.ErrMemberNotFound
+	}
+
+	return nil
+}
+
+func (db *SQLiteDB) DeleteGroupMember(ctx context.Context, logger *slog.Logger, groupId uint64, userId uint64) error {
+	query := "DELETE FROM group_members WHERE group_id = ? AND user_id = ?;"
+
+	result, err := db.ExecContext(ctx, query, groupId, userId)
+	if err != nil {
+		return queryError(ctx, logger, "Exec DeleteGroupMember", err)
+	}
+
+	n, err := result.RowsAffected()
+	if err != nil {
+		logger.LogAttrs(ctx, slog.LevelWarn, "RowsAffected DeleteGroupMember", slog.String("err", err.Error()))
+	}
+
+	if n != 1 {
+		logger.LogAttrs(ctx, slog.LevelWarn, "RowsAffected DeleteGroupMember", slog.String("err", "There were no rows affected"))
+		return util.ErrMemberNotFound
+	}
+
+	return nil
+}
+
+func (db *SQLiteDB) GetMemberFocusTime(ctx context.Context, logger *slog.Logger, userId uint64, from time.Time, to time.Time) (int64, error) {
+	query := "SELECT COALESCE(SUM(" + focusedSeconds + "), 0) FROM work_logs w WHERE w.user_id = :user_id AND " + focusedWorkLog + ";"
+	row := db.QueryRowContext(ctx, query, sql.Named("user_id", userId), sql.Named("from", from), sql.Named("to", to))
+
+	duration := int64(0)
+	if err := row.Scan(&duration); err != nil {
+		return 0, queryError(ctx, logger, "Scan GetMemberFocusTime", err)
+	}
+	return duration, nil
+}
@@ -0,0 +1,120 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/NerdBow/Grinders-API/internal/util"
+)
+
+// pendingInvite is the condition on the group_invites table for invites that can still be used at a time.
+const pendingInvite = "expiration_time > ? AND (max_uses = 0 OR uses < max_uses)"
+
+func (db *SQLiteDB) AddGroupInvite(ctx context.Context, logger *slog.Logger, invite util.GroupInvite) (uint64, error) {
+	query := `INSERT INTO group_invites
+	(group_id, hashed_code, max_uses, uses, expiration_time, creation_time) VALUES
+	(?, ?, ?, ?, ?, ?);`
+
+	result, err := db.ExecContext(ctx, query, invite.GroupId, invite.HashedCode, invite.MaxUses, invite.Uses, invite.ExpirationTime, invite.CreationTime)
+	if err != nil {
+		return 0, queryError(ctx, logger, "Exec AddGroupInvite", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, queryError(ctx, logger, "LastInsertId AddGroupInvite", err)
+	}
+
+	return uint64(id), nil
+}
+
+func (db *SQLiteDB) GetGroupInvites(ctx context.Context, logger *slog.Logger, groupId uint64, now time.Time) ([]util.GroupInvite, error) {
+	query := `SELECT id, group_id, max_uses, uses, expiration_time, creation_time FROM group_invites
+	WHERE group_id = ? AND ` + pendingInvite + `
+	ORDER BY creation_time ASC;`
+	rows, err := db.QueryContext(ctx, query, groupId, now)
+	if err != nil {
+		return nil, queryError(ctx, logger, "Query GetGroupInvites", err)
+	}
+	defer rows.Close()
+
+	invites := make([]util.GroupInvite, 0, 5)
+	for rows.Next() {
+		invite := util.GroupInvite{}
+		err := rows.Scan(&invite.Id, &invite.GroupId, &invite.MaxUses, &invite.Uses, &invite.ExpirationTime, &invite.CreationTime)
+		if err != nil {
+			return nil, queryError(ctx, logger, "Scan GetGroupInvites", err)
+		}
+		invites = append(invites, invite)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, queryError(ctx, logger, "Rows GetGroupInvites", err)
+	}
+
+	return invites, nil
+}
+
+func (db *SQLiteDB) DeleteGroupInvite(ctx context.Context, logger *slog.Logger, inviteId uint64, groupId uint64) error {
+	query := "DELETE FROM group_invites WHERE id = ? AND group_id = ?;"
+
+	result, err := db.ExecContext(ctx, query, inviteId, groupId)
+	if err != nil {
+		return queryError(ctx, logger, "Exec DeleteGroupInvite", err)
+	}
+
+	n, err := result.RowsAffected()
+	if err != nil {
+		logger.LogAttrs(ctx, slog.LevelWarn, "RowsAffected DeleteGroupInvite", slog.String("err", err.Error()))
+	}
+
+	if n != 1 {
+		logger.LogAttrs(ctx, slog.LevelWarn, "RowsAffected DeleteGroupInvite", slog.String("err", "There were no rows affected"))
+		return util.ErrInviteNotFound
+	}
+
+	return nil
+}
+
+func (db *SQLiteDB) RedeemGroupInvite(ctx context.Context, logger *slog.Logger, hashedCode string, userId uint64, now time.Time) (uint64, error) {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, queryError(ctx, logger, "Begin RedeemGroupInvite", err)
+	}
+	defer tx.Rollback()
+
+	query := "SELECT id, group_id FROM group_invites WHERE hashed_code = ? AND " + pendingInvite + ";"
+	row := tx.QueryRowContext(ctx, query, hashedCode, now)
+
+	inviteId, groupId := uint64(0), uint64(0)
+	err = row.Scan(&inviteId, &groupId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, util.ErrInviteNotFound
+	}
+	if err != nil {
+		return 0, queryError(ctx, logger, "Scan RedeemGroupInvite", err)
+	}
+
+	query = "INSERT INTO group_members (group_id, user_id, role) VALUES (?, ?, ?);"
+	_, err = tx.ExecContext(ctx, query, groupId, userId, util.ROLE_MEMBER)
+	if isUniqueViolation(err) {
+		return 0, util.ErrAlreadyMember
+	}
+	if err != nil {
+		return 0, queryError(ctx, logger, "Exec RedeemGroupInvite AddMember", err)
+	}
+
+	query = "UPDATE group_invites SET uses = uses + 1 WHERE id = ?;"
+	_, err = tx.ExecContext(ctx, query, inviteId)
+	if err != nil {
+		return 0, queryError(ctx, logger, "Exec RedeemGroupInvite CountUse", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return 0, queryError(ctx, logger, "Commit RedeemGroupInvite", err)
+	}
+
+	return groupId, nil
+}
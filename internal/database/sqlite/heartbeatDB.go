@@ -0,0 +1,80 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/NerdBow/Grinders-API/internal/util"
+)
+
+const heartbeatColumns = "h.user_id, h.last_heartbeat, h.idle_threshold, h.idle_time"
+
+func scanHeartbeat(row scanner) (util.Heartbeat, error) {
+	heartbeat := util.Heartbeat{}
+	err := row.Scan(&heartbeat.UserId, &heartbeat.LastHeartbeat, &heartbeat.IdleThreshold, &heartbeat.IdleTime)
+	return heartbeat, err
+}
+
+func (db *SQLiteDB) GetHeartbeat(ctx context.Context, logger *slog.Logger, userId uint64) (util.Heartbeat, error) {
+	query := "SELECT " + heartbeatColumns + " FROM heartbeats h WHERE h.user_id = ?;"
+	row := db.QueryRowContext(ctx, query, userId)
+
+	heartbeat, err := scanHeartbeat(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return util.Heartbeat{UserId: userId}, nil
+	}
+	if err != nil {
+		return heartbeat, queryError(ctx, logger, "Scan GetHeartbeat", err)
+	}
+	return heartbeat, nil
+}
+
+func (db *SQLiteDB) SetHeartbeat(ctx context.Context, logger *slog.Logger, heartbeat util.Heartbeat) error {
+	query := `INSERT INTO heartbeats (user_id, last_heartbeat, idle_threshold, idle_time) VALUES (?, ?, ?, ?)
+	ON CONFLICT(user_id) DO UPDATE SET last_heartbeat = excluded.last_heartbeat, idle_threshold = excluded.idle_threshold, idle_time = excluded.idle_time;`
+
+	result, err := db.ExecContext(ctx, query, heartbeat.UserId, heartbeat.LastHeartbeat, heartbeat.IdleThreshold, heartbeat.IdleTime)
+	if err != nil {
+		return queryError(ctx, logger, "Exec SetHeartbeat", err)
+	}
+
+	n, err := result.RowsAffected()
+	if err != nil {
+		logger.LogAttrs(ctx, slog.LevelWarn, "RowsAffected SetHeartbeat", slog.String("err", err.Error()))
+	}
+
+	if n != 1 {
+		logger.LogAttrs(ctx, slog.LevelWarn, "RowsAffected SetHeartbeat", slog.String("err", "There were no rows affected"))
+	}
+
+	return nil
+}
+
+func (db *SQLiteDB) GetIdleHeartbeats(ctx context.Context, logger *slog.Logger, now time.Time) ([]util.Heartbeat, error) {
+	query := `SELECT ` + heartbeatColumns + ` FROM heartbeats h
+	INNER JOIN work_logs w ON w.user_id = h.user_id AND w.is_complete = ?
+	WHERE h.last_heartbeat != ? AND h.idle_time <= ?
+	AND NOT EXISTS (SELECT 1 FROM pauses p WHERE p.work_log_id = w.id AND p.end_time = ?);`
+	rows, err := db.QueryContext(ctx, query, false, time.Time{}, now, time.Time{})
+	if err != nil {
+		return nil, queryError(ctx, logger, "Query GetIdleHeartbeats", err)
+	}
+	defer rows.Close()
+
+	heartbeats := make([]util.Heartbeat, 0, 10)
+	for rows.Next() {
+		heartbeat, err := scanHeartbeat(rows)
+		if err != nil {
+			return nil, queryError(ctx, logger, "Scan GetIdleHeartbeats", err)
+		}
+		heartbeats = append(heartbeats, heartbeat)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, queryError(ctx, logger, "Rows GetIdleHeartbeats", err)
+	}
+
+	return heartbeats, nil
+}
@@ -0,0 +1,70 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/NerdBow/Grinders-API/internal/util"
+)
+
+func (db *SQLiteDB) GetLeaderboard(ctx context.Context, logger *slog.Logger, groupId uint64, from time.Time, to time.Time) ([]util.LeaderboardEntry, error) {
+	query := `SELECT RANK() OVER (ORDER BY focused DESC, completed DESC), user_id, username, focused, completed FROM (
+		SELECT m.user_id, u.username,
+		(SELECT COALESCE(SUM(` + focusedSeconds + `), 0) FROM work_logs w
+			WHERE w.user_id = m.user_id AND ` + focusedWorkLog + `) AS focused,
+		(SELECT COUNT(*) FROM tasks t
+			WHERE t.user_id = m.user_id AND t.is_completed = :completed AND t.completion_time >= :from AND t.completion_time < :to) AS completed
+		FROM group_members m INNER JOIN users u ON m.user_id = u.id
+		LEFT JOIN leaderboard_opt_outs o ON m.user_id = o.user_id
+		WHERE m.group_id = :group_id AND o.user_id IS NULL
+	) ORDER BY focused DESC, completed DESC, username ASC;`
+	rows, err := db.QueryContext(ctx, query, sql.Named("completed", true), sql.Named("from", from), sql.Named("to", to), sql.Named("group_id", groupId))
+	if err != nil {
+		return nil, queryError(ctx, logger, "Query GetLeaderboard", err)
+	}
+	defer rows.Close()
+
+	entries := make([]util.LeaderboardEntry, 0, 10)
+	for rows.Next() {
+		entry := util.LeaderboardEntry{}
+		if err := rows.Scan(&entry.Rank, &entry.UserId, &entry.Username, &entry.FocusedDuration, &entry.CompletedTasks); err != nil {
+			return nil, queryError(ctx, logger, "Scan GetLeaderboard", err)
+		}
+		entries = append(entries, entry)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, queryError(ctx, logger, "Rows GetLeaderboard", err)
+	}
+
+	return entries, nil
+}
+
+func (db *SQLiteDB) GetLeaderboardOptOut(ctx context.Context, logger *slog.Logger, userId uint64) (bool, error) {
+	query := "SELECT user_id FROM leaderboard_opt_outs WHERE user_id = ?;"
+	row := db.QueryRowContext(ctx, query, userId)
+
+	err := row.Scan(&userId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, queryError(ctx, logger, "Scan GetLeaderboardOptOut", err)
+	}
+	return true, nil
+}
+
+func (db *SQLiteDB) SetLeaderboardOptOut(ctx context.Context, logger *slog.Logger, userId uint64, optOut bool) error {
+	query := "DELETE FROM leaderboard_opt_outs WHERE user_id = ?;"
+	if optOut {
+		query = "INSERT INTO leaderboard_opt_outs (user_id) VALUES (?) ON CONFLICT(user_id) DO NOTHING;"
+	}
+
+	_, err := db.ExecContext(ctx, query, userId)
+	if err != nil {
+		return queryError(ctx, logger, "Exec SetLeaderboardOptOut", err)
+	}
+	return nil
+}
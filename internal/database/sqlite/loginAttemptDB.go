@@ -0,0 +1,110 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/NerdBow/Grinders-API/internal/util"
+)
+
+func (db *SQLiteDB) AddLoginFailure(ctx context.Context, logger *slog.Logger, key string, now time.Time, resetBefore time.Time, lockedUntil []time.Time) (util.LoginAttempt, error) {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return util.LoginAttempt{}, queryError(ctx, logger, "Begin AddLoginFailure", err)
+	}
+	defer tx.Rollback()
+
+	// Expired attempts of every key are deleted, so made up usernames do not keep their rows.
+	query := "DELETE FROM login_attempts WHERE last_failure < ? AND locked_until <= ?;"
+	_, err = tx.ExecContext(ctx, query, resetBefore, now)
+	if err != nil {
+		return util.LoginAttempt{}, queryError(ctx, logger, "Exec AddLoginFailure Expired", err)
+	}
+
+	// The amount of failures including this one picks the lock time, the last lock time is used for any amount after it.
+	query = `INSERT INTO login_attempts (key, failures, last_failure, locked_until) VALUES (?, 1, ?, ?)
+	ON CONFLICT(key) DO UPDATE SET failures = failures + 1, last_failure = excluded.last_failure,
+	locked_until = CASE MIN(failures + 1, ?)` + strings.Repeat(" WHEN ? THEN ?", len(lockedUntil)) + ` END
+	WHERE login_attempts.locked_until <= excluded.last_failure
+	RETURNING key, failures, last_failure, locked_until;`
+	params := []any{key, now, lockedUntil[0], len(lockedUntil)}
+	for i, until := range lockedUntil {
+		params = append(params, i+1, until)
+	}
+	row := tx.QueryRowContext(ctx, query, params...)
+
+	attempt := util.LoginAttempt{}
+	err = row.Scan(&attempt.Key, &attempt.Failures, &attempt.LastFailure, &attempt.LockedUntil)
+	if errors.Is(err, sql.ErrNoRows) {
+		// The key is locked, so the failure was not counted.
+		query = "SELECT key, failures, last_failure, locked_until FROM login_attempts WHERE key = ?;"
+		row = tx.QueryRowContext(ctx, query, key)
+		err = row.Scan(&attempt.Key, &attempt.Failures, &attempt.LastFailure, &attempt.LockedUntil)
+		if err != nil {
+			return attempt, queryError(ctx, logger, "Scan AddLoginFailure Locked", err)
+		}
+		return attempt, util.ErrLoginThrottled
+	}
+	if err != nil {
+		return attempt, queryError(ctx, logger, "Scan AddLoginFailure", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return attempt, queryError(ctx, logger, "Commit AddLoginFailure", err)
+	}
+
+	return attempt, nil
+}
+
+func (db *SQLiteDB) RemoveLoginFailure(ctx context.Context, logger *slog.Logger, attempt util.LoginAttempt) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return queryError(ctx, logger, "Begin RemoveLoginFailure", err)
+	}
+	defer tx.Rollback()
+
+	// The lock is only lifted if no failure was counted after the attempt.
+	query := `UPDATE login_attempts SET failures = failures - 1,
+	locked_until = CASE WHEN locked_until = ? THEN last_failure ELSE locked_until END
+	WHERE key = ? AND failures > 0;`
+	result, err := tx.ExecContext(ctx, query, attempt.LockedUntil, attempt.Key)
+	if err != nil {
+		return queryError(ctx, logger, "Exec RemoveLoginFailure", err)
+	}
+
+	n, err := result.RowsAffected()
+	if err != nil {
+		logger.LogAttrs(ctx, slog.LevelWarn, "RowsAffected RemoveLoginFailure", slog.String("err", err.Error()))
+	}
+
+	if n != 1 {
+		logger.LogAttrs(ctx, slog.LevelWarn, "RowsAffected RemoveLoginFailure", slog.String("err", "There were no rows affected"))
+	}
+
+	query = "DELETE FROM login_attempts WHERE key = ? AND failures = 0;"
+	_, err = tx.ExecContext(ctx, query, attempt.Key)
+	if err != nil {
+		return queryError(ctx, logger, "Exec RemoveLoginFailure Delete", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return queryError(ctx, logger, "Commit RemoveLoginFailure", err)
+	}
+
+	return nil
+}
+
+func (db *SQLiteDB) DeleteLoginAttempt(ctx context.Context, logger *slog.Logger, key string) error {
+	query := "DELETE FROM login_attempts WHERE key = ?;"
+
+	_, err := db.ExecContext(ctx, query, key)
+	if err != nil {
+		return queryError(ctx, logger, "Exec DeleteLoginAttempt", err)
+	}
+
+	return nil
+}
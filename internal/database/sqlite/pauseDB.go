@@ -0,0 +1,96 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/NerdBow/Grinders-API/internal/util"
+)
+
+const pauseColumns = "id, start_time, duration, end_time, work_log_id"
+
+func scanPause(row scanner) (util.Pause, error) {
+	pause := util.Pause{}
+	err := row.Scan(&pause.Id, &pause.StartTime, &pause.Duration, &pause.EndTime, &pause.WorkLogId)
+	return pause, err
+}
+
+func (db *SQLiteDB) AddPause(ctx context.Context, logger *slog.Logger, pause util.Pause) (uint64, error) {
+	query := "INSERT INTO pauses (start_time, duration, end_time, work_log_id) VALUES (?, ?, ?, ?);"
+
+	result, err := db.ExecContext(ctx, query, pause.StartTime, pause.Duration, pause.EndTime, pause.WorkLogId)
+	if isUniqueViolation(err) {
+		return 0, util.ErrWorkLogPaused
+	}
+	if err != nil {
+		return 0, queryError(ctx, logger, "Exec AddPause", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, queryError(ctx, logger, "LastInsertId AddPause", err)
+	}
+
+	return uint64(id), nil
+}
+
+func (db *SQLiteDB) GetRunningPause(ctx context.Context, logger *slog.Logger, workLogId uint64) (util.Pause, error) {
+	query := "SELECT " + pauseColumns + " FROM pauses WHERE work_log_id = ? AND end_time = ?;"
+	row := db.QueryRowContext(ctx, query, workLogId, time.Time{})
+
+	pause, err := scanPause(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return pause, util.ErrWorkLogNotPaused
+	}
+	if err != nil {
+		return pause, queryError(ctx, logger, "Scan GetRunningPause", err)
+	}
+	return pause, nil
+}
+
+func (db *SQLiteDB) GetPauses(ctx context.Context, logger *slog.Logger, workLogId uint64) ([]util.Pause, error) {
+	query := "SELECT " + pauseColumns + " FROM pauses WHERE work_log_id = ? ORDER BY start_time ASC;"
+	rows, err := db.QueryContext(ctx, query, workLogId)
+	if err != nil {
+		return nil, queryError(ctx, logger, "Query GetPauses", err)
+	}
+	defer rows.Close()
+
+	pauses := make([]util.Pause, 0, 10)
+	for rows.Next() {
+		pause, err := scanPause(rows)
+		if err != nil {
+			return nil, queryError(ctx, logger, "Scan GetPauses", err)
+		}
+		pauses = append(pauses, pause)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, queryError(ctx, logger, "Rows GetPauses", err)
+	}
+
+	return pauses, nil
+}
+
+func (db *SQLiteDB) EndPause(ctx context.Context, logger *slog.Logger, pause util.Pause) error {
+	query := "UPDATE pauses SET end_time = ?, duration = ? WHERE id = ? AND end_time = ?;"
+
+	result, err := db.ExecContext(ctx, query, pause.EndTime, pause.Duration, pause.Id, time.Time{})
+	if err != nil {
+		return queryError(ctx, logger, "Exec EndPause", err)
+	}
+
+	n, err := result.RowsAffected()
+	if err != nil {
+		logger.LogAttrs(ctx, slog.LevelWarn, "RowsAffected EndPause", slog.String("err", err.Error()))
+	}
+
+	if n != 1 {
+		logger.LogAttrs(ctx, slog.LevelWarn, "RowsAffected EndPause", slog.String("err", "There were no rows affected"))
+		return util.ErrWorkLogNotPaused
+	}
+
+	return nil
+}
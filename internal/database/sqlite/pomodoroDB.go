@@ -0,0 +1,155 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/NerdBow/Grinders-API/internal/util"
+)
+
+const pomodoroColumns = "user_id, phase, cycle, task_id, objective, phase_start, phase_end, work_log_id, break_id"
+
+func scanPomodoro(row scanner) (util.Pomodoro, error) {
+	pomodoro := util.Pomodoro{}
+	err := row.Scan(&pomodoro.UserId, &pomodoro.Phase, &pomodoro.Cycle, &pomodoro.TaskId, &pomodoro.Objective,
+		&pomodoro.PhaseStart, &pomodoro.PhaseEnd, &pomodoro.WorkLogId, &pomodoro.BreakId)
+	return pomodoro, err
+}
+
+func (db *SQLiteDB) GetPomodoroSettings(ctx context.Context, logger *slog.Logger, userId uint64) (util.PomodoroSettings, error) {
+	query := `SELECT user_id, focus_length, short_break_length, long_break_length, cycles_before_long_break
+	FROM pomodoro_settings WHERE user_id = ?;`
+	row := db.QueryRowContext(ctx, query, userId)
+
+	settings := util.PomodoroSettings{}
+	err := row.Scan(&settings.UserId, &settings.FocusLength, &settings.ShortBreakLength, &settings.LongBreakLength, &settings.CyclesBeforeLongBreak)
+	if errors.Is(err, sql.ErrNoRows) {
+		return util.PomodoroSettings{UserId: userId}, nil
+	}
+	if err != nil {
+		return settings, queryError(ctx, logger, "Scan GetPomodoroSettings", err)
+	}
+	return settings, nil
+}
+
+func (db *SQLiteDB) SetPomodoroSettings(ctx context.Context, logger *slog.Logger, settings util.PomodoroSettings) error {
+	query := `INSERT INTO pomodoro_settings (user_id, focus_length, short_break_length, long_break_length, cycles_before_long_break)
+	VALUES (?, ?, ?, ?, ?)
+	ON CONFLICT(user_id) DO UPDATE SET focus_length = excluded.focus_length, short_break_length = excluded.short_break_length,
+	long_break_length = excluded.long_break_length, cycles_before_long_break = excluded.cycles_before_long_break;`
+
+	result, err := db.ExecContext(ctx, query, settings.UserId, settings.FocusLength, settings.ShortBreakLength, settings.LongBreakLength, settings.CyclesBeforeLongBreak)
+	if err != nil {
+		return queryError(ctx, logger, "Exec SetPomodoroSettings", err)
+	}
+
+	n, err := result.RowsAffected()
+	if err != nil {
+		logger.LogAttrs(ctx, slog.LevelWarn, "RowsAffected SetPomodoroSettings", slog.String("err", err.Error()))
+	}
+
+	if n != 1 {
+		logger.LogAttrs(ctx, slog.LevelWarn, "RowsAffected SetPomodoroSettings", slog.String("err", "There were no rows affected"))
+	}
+
+	return nil
+}
+
+func (db *SQLiteDB) GetPomodoro(ctx context.Context, logger *slog.Logger, userId uint64) (util.Pomodoro, error) {
+	query := "SELECT " + pomodoroColumns + " FROM pomodoros WHERE user_id = ?;"
+	row := db.QueryRowContext(ctx, query, userId)
+
+	pomodoro, err := scanPomodoro(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return pomodoro, util.ErrPomodoroNotFound
+	}
+	if err != nil {
+		return pomodoro, queryError(ctx, logger, "Scan GetPomodoro", err)
+	}
+	return pomodoro, nil
+}
+
+func (db *SQLiteDB) GetDuePomodoros(ctx context.Context, logger *slog.Logger, now time.Time) ([]util.Pomodoro, error) {
+	query := "SELECT " + pomodoroColumns + " FROM pomodoros WHERE phase != ? AND phase_end <= ?;"
+	rows, err := db.QueryContext(ctx, query, util.POMODORO_IDLE, now)
+	if err != nil {
+		return nil, queryError(ctx, logger, "Query GetDuePomodoros", err)
+	}
+	defer rows.Close()
+
+	pomodoros := make([]util.Pomodoro, 0, 10)
+	for rows.Next() {
+		pomodoro, err := scanPomodoro(rows)
+		if err != nil {
+			return nil, queryError(ctx, logger, "Scan GetDuePomodoros", err)
+		}
+		pomodoros = append(pomodoros, pomodoro)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, queryError(ctx, logger, "Rows GetDuePomodoros", err)
+	}
+
+	return pomodoros, nil
+}
+
+func (db *SQLiteDB) AddPomodoro(ctx context.Context, logger *slog.Logger, pomodoro util.Pomodoro) error {
+	query := "INSERT INTO pomodoros (" + pomodoroColumns + ") VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?);"
+
+	_, err := db.ExecContext(ctx, query, pomodoro.UserId, pomodoro.Phase, pomodoro.Cycle, pomodoro.TaskId, pomodoro.Objective,
+		pomodoro.PhaseStart, pomodoro.PhaseEnd, pomodoro.WorkLogId, pomodoro.BreakId)
+	if isUniqueViolation(err) {
+		return util.ErrPomodoroChanged
+	}
+	if err != nil {
+		return queryError(ctx, logger, "Exec AddPomodoro", err)
+	}
+
+	return nil
+}
+
+func (db *SQLiteDB) UpdatePomodoro(ctx context.Context, logger *slog.Logger, pomodoro util.Pomodoro, previous util.Pomodoro) error {
+	query := `UPDATE pomodoros SET phase = ?, cycle = ?, task_id = ?, objective = ?, phase_start = ?, phase_end = ?, work_log_id = ?, break_id = ?
+	WHERE user_id = ? AND phase = ? AND phase_end = ?;`
+
+	result, err := db.ExecContext(ctx, query, pomodoro.Phase, pomodoro.Cycle, pomodoro.TaskId, pomodoro.Objective, pomodoro.PhaseStart,
+		pomodoro.PhaseEnd, pomodoro.WorkLogId, pomodoro.BreakId, pomodoro.UserId, previous.Phase, previous.PhaseEnd)
+	if err != nil {
+		return queryError(ctx, logger, "Exec UpdatePomodoro", err)
+	}
+
+	n, err := result.RowsAffected()
+	if err != nil {
+		logger.LogAttrs(ctx, slog.LevelWarn, "RowsAffected UpdatePomodoro", slog.String("err", err.Error()))
+	}
+
+	if n != 1 {
+		logger.LogAttrs(ctx, slog.LevelInfo, "RowsAffected UpdatePomodoro", slog.String("err", "The pomodoro changed phase"))
+		return util.ErrPomodoroChanged
+	}
+
+	return nil
+}
+
+func (db *SQLiteDB) DeletePomodoro(ctx context.Context, logger *slog.Logger, pomodoro util.Pomodoro) error {
+	query := "DELETE FROM pomodoros WHERE user_id = ? AND phase = ? AND phase_end = ?;"
+
+	result, err := db.ExecContext(ctx, query, pomodoro.UserId, pomodoro.Phase, pomodoro.PhaseEnd)
+	if err != nil {
+		return queryError(ctx, logger, "Exec DeletePomodoro", err)
+	}
+
+	n, err := result.RowsAffected()
+	if err != nil {
+		logger.LogAttrs(ctx, slog.LevelWarn, "RowsAffected DeletePomodoro", slog.String("err", err.Error()))
+	}
+
+	if n != 1 {
+		logger.LogAttrs(ctx, slog.LevelInfo, "RowsAffected DeletePomodoro", slog.String("err", "The pomodoro changed phase"))
+		return util.ErrPomodoroChanged
+	}
+
+	return nil
+}
@@ -0,0 +1,142 @@
+package sqlite
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/NerdBow/Grinders-API/internal/util"
+)
+
+func (db *SQLiteDB) GetStreaks(ctx context.Context, logger *slog.Logger, userId uint64) ([]util.Streak, error) {
+	query := "SELECT user_id, goal_id, current, longest, checked_until FROM streaks WHERE user_id = ? ORDER BY goal_id ASC;"
+	rows, err := db.QueryContext(ctx, query, userId)
+	if err != nil {
+		return nil, queryError(ctx, logger, "Query GetStreaks", err)
+	}
+	defer rows.Close()
+
+	streaks := make([]util.Streak, 0, 5)
+	for rows.Next() {
+		streak := util.Streak{}
+		err := rows.Scan(&streak.UserId, &streak.GoalId, &streak.Current, &streak.Longest, &streak.CheckedUntil)
+		if err != nil {
+			return nil, queryError(ctx, logger, "Scan GetStreaks", err)
+		}
+		streaks = append(streaks, streak)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, queryError(ctx, logger, "Rows GetStreaks", err)
+	}
+
+	return streaks, nil
+}
+
+func (db *SQLiteDB) SaveStreak(ctx context.Context, logger *slog.Logger, streak util.Streak) error {
+	query := `INSERT INTO streaks (user_id, goal_id, current, longest, checked_until) VALUES (?, ?, ?, ?, ?)
+	ON CONFLICT(user_id, goal_id) DO UPDATE SET current = excluded.current, longest = excluded.longest, checked_until = excluded.checked_until;`
+
+	result, err := db.ExecContext(ctx, query, streak.UserId, streak.GoalId, streak.Current, streak.Longest, streak.CheckedUntil)
+	if err != nil {
+		return queryError(ctx, logger, "Exec SaveStreak", err)
+	}
+
+	n, err := result.RowsAffected()
+	if err != nil {
+		logger.LogAttrs(ctx, slog.LevelWarn, "RowsAffected SaveStreak", slog.String("err", err.Error()))
+	}
+
+	if n != 1 {
+		logger.LogAttrs(ctx, slog.LevelWarn, "RowsAffected SaveStreak", slog.String("err", "There were no rows affected"))
+	}
+
+	return nil
+}
+
+func (db *SQLiteDB) ResetStreaks(ctx context.Context, logger *slog.Logger, userId uint64, from time.Time) error {
+	query := "DELETE FROM streaks WHERE user_id = ? AND checked_until > ?;"
+
+	_, err := db.ExecContext(ctx, query, userId, from)
+	if err != nil {
+		return queryError(ctx, logger, "Exec ResetStreaks", err)
+	}
+	return nil
+}
+
+func (db *SQLiteDB) DeleteGoalStreak(ctx context.Context, logger *slog.Logger, goalId uint64) error {
+	query := "DELETE FROM streaks WHERE goal_id = ?;"
+
+	_, err := db.ExecContext(ctx, query, goalId)
+	if err != nil {
+		return queryError(ctx, logger, "Exec DeleteGoalStreak", err)
+	}
+	return nil
+}
+
+func (db *SQLiteDB) AddRestDay(ctx context.Context, logger *slog.Logger, userId uint64, date string) error {
+	query := "INSERT INTO rest_days (user_id, date) VALUES (?, ?) ON CONFLICT(user_id, date) DO NOTHING;"
+
+	_, err := db.ExecContext(ctx, query, userId, date)
+	if err != nil {
+		return queryError(ctx, logger, "Exec AddRestDay", err)
+	}
+	return nil
+}
+
+func (db *SQLiteDB) GetRestDays(ctx context.Context, logger *slog.Logger, userId uint64, from string, to string) ([]string, error) {
+	query := "SELECT date FROM rest_days WHERE user_id = ?"
+
+	params := make([]any, 0, 3)
+	params = append(params, userId)
+
+	if from != "" {
+		query += " AND date >= ?"
+		params = append(params, from)
+	}
+	if to != "" {
+		query += " AND date <= ?"
+		params = append(params, to)
+	}
+	query += " ORDER BY date ASC;"
+
+	rows, err := db.QueryContext(ctx, query, params...)
+	if err != nil {
+		return nil, queryError(ctx, logger, "Query GetRestDays", err)
+	}
+	defer rows.Close()
+
+	dates := make([]string, 0, 10)
+	for rows.Next() {
+		date := ""
+		if err := rows.Scan(&date); err != nil {
+			return nil, queryError(ctx, logger, "Scan GetRestDays", err)
+		}
+		dates = append(dates, date)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, queryError(ctx, logger, "Rows GetRestDays", err)
+	}
+
+	return dates, nil
+}
+
+func (db *SQLiteDB) DeleteRestDay(ctx context.Context, logger *slog.Logger, userId uint64, date string) error {
+	query := "DELETE FROM rest_days WHERE user_id = ? AND date = ?;"
+
+	result, err := db.ExecContext(ctx, query, userId, date)
+	if err != nil {
+		return queryError(ctx, logger, "Exec DeleteRestDay", err)
+	}
+
+	n, err := result.RowsAffected()
+	if err != nil {
+		logger.LogAttrs(ctx, slog.LevelWarn, "RowsAffected DeleteRestDay", slog.String("err", err.Error()))
+	}
+
+	if n != 1 {
+		logger.LogAttrs(ctx, slog.LevelWarn, "RowsAffected DeleteRestDay", slog.String("err", "There were no rows affected"))
+		return util.ErrRestDayNotFound
+	}
+
+	return nil
+}
@@ -2,53 +2,62 @@ package sqlite
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"log/slog"
+	"strings"
 	"time"
 
 	"github.com/NerdBow/Grinders-API/internal/util"
 )
 
-func (db *SQLiteDB) AddTask(logger *slog.Logger, task util.Task) error {
+func (db *SQLiteDB) AddTask(ctx context.Context, logger *slog.Logger, task util.Task) (uint64, error) {
 	query := `INSERT INTO tasks 
 	(name, creation_time, deadline_time, completion_time, is_completed, category_id, user_id) VALUES 
 	(?, ?, ?, ?, ?, ?, ?);`
 
-	result, err := db.Exec(query, task.Name, task.CreationTime, task.DeadlineTime, time.Time{}, false, task.CategoryId, task.UserId)
+	result, err := db.ExecContext(ctx, query, task.Name, task.CreationTime, task.DeadlineTime, time.Time{}, false, task.CategoryId, task.UserId)
 	if err != nil {
-		logger.LogAttrs(context.Background(), slog.LevelError, "Exec AddTask", slog.String("err", err.Error()))
-		return util.ErrDatabase
+		return 0, queryError(ctx, logger, "Exec AddTask", err)
 	}
 
 	n, err := result.RowsAffected()
 	if err != nil {
-		logger.LogAttrs(context.Background(), slog.LevelWarn, "RowsAffected AddTask", slog.String("err", err.Error()))
+		logger.LogAttrs(ctx, slog.LevelWarn, "RowsAffected AddTask", slog.String("err", err.Error()))
 	}
 
 	if n != 1 {
-		logger.LogAttrs(context.Background(), slog.LevelWarn, "RowsAffected AddTask", slog.String("err", "There were no rows affected"))
+		logger.LogAttrs(ctx, slog.LevelWarn, "RowsAffected AddTask", slog.String("err", "There were no rows affected"))
 	}
 
-	return nil
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, queryError(ctx, logger, "LastInsertId AddTask", err)
+	}
+
+	return uint64(id), nil
 }
 
-func (db *SQLiteDB) GetTask(logger *slog.Logger, taskId uint64, userId uint64) (util.Task, error) {
+func (db *SQLiteDB) GetTask(ctx context.Context, logger *slog.Logger, taskId uint64, userId uint64) (util.Task, error) {
 	query := "SELECT id, name, creation_time, completion_time, deadline_time, is_completed, category_id, user_id FROM tasks WHERE id = ? AND user_id = ?;"
-	row := db.QueryRow(query, taskId, userId)
+	row := db.QueryRowContext(ctx, query, taskId, userId)
 
 	task := util.Task{}
 	err := row.Scan(&task.Id, &task.Name, &task.CreationTime, &task.CompletionTime, &task.DeadlineTime, &task.IsComplete, &task.CategoryId, &task.UserId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return task, util.ErrTaskNotFound
+	}
 	if err != nil {
-		logger.LogAttrs(context.Background(), slog.LevelError, "Scan GetTask", slog.String("err", err.Error()))
-		return task, err
+		return task, queryError(ctx, logger, "Scan GetTask", err)
 	}
 	return task, nil
 }
 
-func (db *SQLiteDB) QueryTask(logger *slog.Logger, querySettings util.TaskQuerySettings) ([]util.Task, error) {
+func (db *SQLiteDB) QueryTask(ctx context.Context, logger *slog.Logger, querySettings util.TaskQuerySettings) ([]util.Task, error) {
 	query := `SELECT 
-	id, name, creation_time, completion_time, deadline_time, is_completed, category_id, user_id
-	FROM tasks 
-	WHERE user_id = ?`
+	t.id, t.name, t.creation_time, t.completion_time, t.deadline_time, t.is_completed, t.category_id, t.user_id
+	FROM tasks t
+	WHERE t.user_id = ?`
 
 	params := make([]any, 0, 3)
 	params = append(params, querySettings.UserId)
@@ -62,7 +71,7 @@ func (db *SQLiteDB) QueryTask(logger *slog.Logger, querySettings util.TaskQueryS
 	}
 
 	if querySettings.Name != "" {
-		query += " AND name LIKE ?"
+		query += " AND t.name LIKE ?"
 		params = append(params, "%"+querySettings.Name+"%")
 	}
 
@@ -71,11 +80,11 @@ func (db *SQLiteDB) QueryTask(logger *slog.Logger, querySettings util.TaskQueryS
 
 		switch querySettings.SortType {
 		case util.SORT_COMPLETION:
-			query += " completion_time"
+			query += " t.completion_time"
 		case util.SORT_CREATION:
-			query += " creation_time"
+			query += " t.creation_time"
 		case util.SORT_DEADLINE:
-			query += " deadline_time"
+			query += " t.deadline_time"
 		}
 
 		switch querySettings.SortOrder {
@@ -89,12 +98,12 @@ func (db *SQLiteDB) QueryTask(logger *slog.Logger, querySettings util.TaskQueryS
 
 	params = append(params, (querySettings.Page-1)*PAGE_SIZE)
 
-	logger.LogAttrs(context.Background(), slog.LevelDebug, "SQL Query QueryTask", slog.String("query", query))
-	rows, err := db.Query(query, params...)
+	logger.LogAttrs(ctx, slog.LevelDebug, "SQL Query QueryTask", slog.String("query", query))
+	rows, err := db.QueryContext(ctx, query, params...)
 	if err != nil {
-		logger.LogAttrs(context.Background(), slog.LevelError, "Query QueryTask", slog.String("err", err.Error()))
-		return nil, err
+		return nil, queryError(ctx, logger, "Query QueryTask", err)
 	}
+	defer rows.Close()
 
 	tasks := make([]util.Task, 0, 20)
 	for rows.Next() {
@@ -102,98 +111,102 @@ func (db *SQLiteDB) QueryTask(logger *slog.Logger, querySettings util.TaskQueryS
 
 		err = rows.Scan(&task.Id, &task.Name, &task.CreationTime, &task.CompletionTime, &task.DeadlineTime, &task.IsComplete, &task.CategoryId, &task.UserId)
 		if err != nil {
-			logger.LogAttrs(context.Background(), slog.LevelError, "Scan QueryTask", slog.String("err", err.Error()))
-			return nil, err
+			return nil, queryError(ctx, logger, "Scan QueryTask", err)
 		}
 		tasks = append(tasks, task)
 	}
+	if err = rows.Err(); err != nil {
+		return nil, queryError(ctx, logger, "Rows QueryTask", err)
+	}
 
 	return tasks, nil
 }
 
-func (db *SQLiteDB) EditTask(logger *slog.Logger, task util.Task) error {
-	query := "UPDATE categories SET"
-
+func (db *SQLiteDB) EditTask(ctx context.Context, logger *slog.Logger, task util.Task) error {
+	columns := make([]string, 0, 5)
 	params := make([]any, 0, 7)
 	if task.Name != "" {
-		query += " name = ?"
+		columns = append(columns, "name = ?")
 		params = append(params, task.Name)
 	}
-	if task.CreationTime.Equal(time.Time{}) {
-		query += " creation_time = ?"
+	if !task.CreationTime.Equal(time.Time{}) {
+		columns = append(columns, "creation_time = ?")
 		params = append(params, task.CreationTime)
 	}
-	if task.CompletionTime.Equal(time.Time{}) {
-		query += " completion_time = ?"
+	if !task.CompletionTime.Equal(time.Time{}) {
+		columns = append(columns, "completion_time = ?")
 		params = append(params, task.CompletionTime)
 	}
-	if task.DeadlineTime.Equal(time.Time{}) {
-		query += " deadline_time = ?"
+	if !task.DeadlineTime.Equal(time.Time{}) {
+		columns = append(columns, "deadline_time = ?")
 		params = append(params, task.DeadlineTime)
 	}
 	if task.CategoryId != 0 {
-		query += " category_id = ?"
+		columns = append(columns, "category_id = ?")
 		params = append(params, task.CategoryId)
 	}
-	query += " WHERE user_id = ? AND id = ?;"
+	if len(columns) == 0 {
+		return nil
+	}
 
+	query := "UPDATE tasks SET " + strings.Join(columns, ", ") + " WHERE user_id = ? AND id = ?;"
 	params = append(params, task.UserId, task.Id)
 
-	result, err := db.Exec(query, params...)
+	result, err := db.ExecContext(ctx, query, params...)
 	if err != nil {
-		logger.LogAttrs(context.Background(), slog.LevelError, "Exec EditTask", slog.String("err", err.Error()))
-		return util.ErrDatabase
+		return queryError(ctx, logger, "Exec EditTask", err)
 	}
 
 	n, err := result.RowsAffected()
 	if err != nil {
-		logger.LogAttrs(context.Background(), slog.LevelWarn, "RowsAffected EditTask", slog.String("err", err.Error()))
+		logger.LogAttrs(ctx, slog.LevelWarn, "RowsAffected EditTask", slog.String("err", err.Error()))
 	}
 
 	if n != 1 {
-		logger.LogAttrs(context.Background(), slog.LevelWarn, "RowsAffected EditTask", slog.String("err", "There were no rows affected"))
+		logger.LogAttrs(ctx, slog.LevelWarn, "RowsAffected EditTask", slog.String("err", "There were no rows affected"))
+		return util.ErrTaskNotFound
 	}
 
 	return nil
 }
 
-func (db *SQLiteDB) DeleteTask(logger *slog.Logger, taskId uint64, userId uint64) error {
+func (db *SQLiteDB) DeleteTask(ctx context.Context, logger *slog.Logger, taskId uint64, userId uint64) error {
 	query := "DELETE FROM tasks WHERE user_id = ? AND id = ?;"
 
-	result, err := db.Exec(query, userId, taskId)
+	result, err := db.ExecContext(ctx, query, userId, taskId)
 	if err != nil {
-		logger.LogAttrs(context.Background(), slog.LevelError, "Exec DeleteTask", slog.String("err", err.Error()))
-		return util.ErrDatabase
+		return queryError(ctx, logger, "Exec DeleteTask", err)
 	}
 
 	n, err := result.RowsAffected()
 	if err != nil {
-		logger.LogAttrs(context.Background(), slog.LevelWarn, "RowsAffected DeleteTask", slog.String("err", err.Error()))
+		logger.LogAttrs(ctx, slog.LevelWarn, "RowsAffected DeleteTask", slog.String("err", err.Error()))
 	}
 
 	if n != 1 {
-		logger.LogAttrs(context.Background(), slog.LevelWarn, "RowsAffected DeleteTask", slog.String("err", "There were no rows affected"))
+		logger.LogAttrs(ctx, slog.LevelWarn, "RowsAffected DeleteTask", slog.String("err", "There were no rows affected"))
+		return util.ErrTaskNotFound
 	}
 
 	return nil
 }
 
-func (db *SQLiteDB) SetTaskCompletion(logger *slog.Logger, taskId uint64, status bool, userId uint64) error {
-	query := "UPDATE tasks SET is_completed = ? WHERE user_id = ? AND id = ?;"
+func (db *SQLiteDB) SetTaskCompletion(ctx context.Context, logger *slog.Logger, taskId uint64, status bool, completionTime time.Time, userId uint64) error {
+	query := "UPDATE tasks SET is_completed = ?, completion_time = ? WHERE user_id = ? AND id = ?;"
 
-	result, err := db.Exec(query, status, userId, taskId)
+	result, err := db.ExecContext(ctx, query, status, completionTime, userId, taskId)
 	if err != nil {
-		logger.LogAttrs(context.Background(), slog.LevelError, "Exec SetTaskCompletion", slog.String("err", err.Error()))
-		return util.ErrDatabase
+		return queryError(ctx, logger, "Exec SetTaskCompletion", err)
 	}
 
 	n, err := result.RowsAffected()
 	if err != nil {
-		logger.LogAttrs(context.Background(), slog.LevelWarn, "RowsAffected SetTaskCompletion", slog.String("err", err.Error()))
+		logger.LogAttrs(ctx, slog.LevelWarn, "RowsAffected SetTaskCompletion", slog.String("err", err.Error()))
 	}
 
 	if n != 1 {
-		logger.LogAttrs(context.Background(), slog.LevelWarn, "RowsAffected SetTaskCompletion", slog.String("err", "There were no rows affected"))
+		logger.LogAttrs(ctx, slog.LevelWarn, "RowsAffected SetTaskCompletion", slog.String("err", "There were no rows affected"))
+		return util.ErrTaskNotFound
 	}
 
 	return nil
@@ -0,0 +1,199 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/NerdBow/Grinders-API/internal/util"
+)
+
+const reviewColumns = "w.id, w.task_id, w.objective, w.work_description, w.is_complete, w.start_time, w.duration, w.end_time, w.user_id, r.reason, r.auto_stop_time"
+
+func scanReview(row scanner) (util.WorkLogReview, error) {
+	review := util.WorkLogReview{}
+	err := row.Scan(&review.Id, &review.TaskId, &review.Objective, &review.WorkDescription, &review.IsComplete, &review.StartTime, &review.Duration, &review.EndTime, &review.UserId,
+		&review.Reason, &review.AutoStopTime)
+	return review, err
+}
+
+func (db *SQLiteDB) GetTimerLimits(ctx context.Context, logger *slog.Logger, userId uint64) (util.TimerLimits, error) {
+	query := "SELECT user_id, max_duration, cutoff_time FROM timer_limits WHERE user_id = ?;"
+	row := db.QueryRowContext(ctx, query, userId)
+
+	limits := util.TimerLimits{}
+	err := row.Scan(&limits.UserId, &limits.MaxDuration, &limits.CutoffTime)
+	if errors.Is(err, sql.ErrNoRows) {
+		return util.TimerLimits{UserId: userId}, nil
+	}
+	if err != nil {
+		return limits, queryError(ctx, logger, "Scan GetTimerLimits", err)
+	}
+	return limits, nil
+}
+
+func (db *SQLiteDB) SetTimerLimits(ctx context.Context, logger *slog.Logger, limits util.TimerLimits) error {
+	query := `INSERT INTO timer_limits (user_id, max_duration, cutoff_time) VALUES (?, ?, ?)
+	ON CONFLICT(user_id) DO UPDATE SET max_duration = excluded.max_duration, cutoff_time = excluded.cutoff_time;`
+
+	result, err := db.ExecContext(ctx, query, limits.UserId, limits.MaxDuration, limits.CutoffTime)
+	if err != nil {
+		return queryError(ctx, logger, "Exec SetTimerLimits", err)
+	}
+
+	n, err := result.RowsAffected()
+	if err != nil {
+		logger.LogAttrs(ctx, slog.LevelWarn, "RowsAffected SetTimerLimits", slog.String("err", err.Error()))
+	}
+
+	if n != 1 {
+		logger.LogAttrs(ctx, slog.LevelWarn, "RowsAffected SetTimerLimits", slog.String("err", "There were no rows affected"))
+	}
+
+	return nil
+}
+
+func (db *SQLiteDB) GetLimitedWorkLogs(ctx context.Context, logger *slog.Logger) ([]util.WorkLog, error) {
+	query := `SELECT w.id, w.task_id, w.objective, w.work_description, w.is_complete, w.start_time, w.duration, w.end_time, w.user_id
+	FROM work_logs w INNER JOIN timer_limits l ON w.user_id = l.user_id
+	WHERE w.is_complete = ? AND (l.max_duration > 0 OR l.cutoff_time != '');`
+	rows, err := db.QueryContext(ctx, query, false)
+	if err != nil {
+		return nil, queryError(ctx, logger, "Query GetLimitedWorkLogs", err)
+	}
+	defer rows.Close()
+
+	workLogs := make([]util.WorkLog, 0, 10)
+	for rows.Next() {
+		workLog, err := scanWorkLog(rows)
+		if err != nil {
+			return nil, queryError(ctx, logger, "Scan GetLimitedWorkLogs", err)
+		}
+		workLogs = append(workLogs, workLog)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, queryError(ctx, logger, "Rows GetLimitedWorkLogs", err)
+	}
+
+	return workLogs, nil
+}
+
+func (db *SQLiteDB) AutoStopWorkLog(ctx context.Context, logger *slog.Logger, stopped util.WorkLog, pause util.Pause, reason string) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return queryError(ctx, logger, "Begin AutoStopWorkLog", err)
+	}
+	defer tx.Rollback()
+
+	if pause.Id != 0 {
+		query := "UPDATE pauses SET end_time = ?, duration = ? WHERE id = ? AND end_time = ?;"
+		_, err = tx.ExecContext(ctx, query, pause.EndTime, pause.Duration, pause.Id, time.Time{})
+		if err != nil {
+			return queryError(ctx, logger, "Exec AutoStopWorkLog EndPause", err)
+		}
+	}
+
+	query := `UPDATE work_logs SET work_description = ?, is_complete = ?, end_time = ?, duration = ?
+	WHERE user_id = ? AND id = ? AND is_complete = ?;`
+	result, err := tx.ExecContext(ctx, query, stopped.WorkDescription, true, stopped.EndTime, stopped.Duration, stopped.UserId, stopped.Id, false)
+	if err != nil {
+		return queryError(ctx, logger, "Exec AutoStopWorkLog StopWorkLog", err)
+	}
+
+	n, err := result.RowsAffected()
+	if err != nil {
+		logger.LogAttrs(ctx, slog.LevelWarn, "RowsAffected AutoStopWorkLog", slog.String("err", err.Error()))
+	}
+	if n != 1 {
+		logger.LogAttrs(ctx, slog.LevelWarn, "RowsAffected AutoStopWorkLog", slog.String("err", "There were no rows affected"))
+		return util.ErrWorkLogNotFound
+	}
+
+	query = "INSERT INTO work_log_reviews (work_log_id, reason, auto_stop_time) VALUES (?, ?, ?);"
+	_, err = tx.ExecContext(ctx, query, stopped.Id, reason, stopped.EndTime)
+	if err != nil {
+		return queryError(ctx, logger, "Exec AutoStopWorkLog AddReview", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return queryError(ctx, logger, "Commit AutoStopWorkLog", err)
+	}
+
+	return nil
+}
+
+func (db *SQLiteDB) GetWorkLogReviews(ctx context.Context, logger *slog.Logger, userId uint64) ([]util.WorkLogReview, error) {
+	query := `SELECT ` + reviewColumns + ` FROM work_log_reviews r INNER JOIN work_logs w ON r.work_log_id = w.id
+	WHERE w.user_id = ? ORDER BY w.start_time ASC;`
+	rows, err := db.QueryContext(ctx, query, userId)
+	if err != nil {
+		return nil, queryError(ctx, logger, "Query GetWorkLogReviews", err)
+	}
+	defer rows.Close()
+
+	reviews := make([]util.WorkLogReview, 0, 10)
+	for rows.Next() {
+		review, err := scanReview(rows)
+		if err != nil {
+			return nil, queryError(ctx, logger, "Scan GetWorkLogReviews", err)
+		}
+		reviews = append(reviews, review)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, queryError(ctx, logger, "Rows GetWorkLogReviews", err)
+	}
+
+	return reviews, nil
+}
+
+func (db *SQLiteDB) GetWorkLogReview(ctx context.Context, logger *slog.Logger, workLogId uint64, userId uint64) (util.WorkLogReview, error) {
+	query := `SELECT ` + reviewColumns + ` FROM work_log_reviews r INNER JOIN work_logs w ON r.work_log_id = w.id
+	WHERE w.id = ? AND w.user_id = ?;`
+	row := db.QueryRowContext(ctx, query, workLogId, userId)
+
+	review, err := scanReview(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return review, util.ErrReviewNotFound
+	}
+	if err != nil {
+		return review, queryError(ctx, logger, "Scan GetWorkLogReview", err)
+	}
+	return review, nil
+}
+
+func (db *SQLiteDB) ResolveWorkLogReview(ctx context.Context, logger *slog.Logger, workLog util.WorkLog) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return queryError(ctx, logger, "Begin ResolveWorkLogReview", err)
+	}
+	defer tx.Rollback()
+
+	query := "DELETE FROM work_log_reviews WHERE work_log_id = ?;"
+	result, err := tx.ExecContext(ctx, query, workLog.Id)
+	if err != nil {
+		return queryError(ctx, logger, "Exec ResolveWorkLogReview DeleteReview", err)
+	}
+
+	n, err := result.RowsAffected()
+	if err != nil {
+		logger.LogAttrs(ctx, slog.LevelWarn, "RowsAffected ResolveWorkLogReview", slog.String("err", err.Error()))
+	}
+	if n != 1 {
+		logger.LogAttrs(ctx, slog.LevelWarn, "RowsAffected ResolveWorkLogReview", slog.String("err", "There were no rows affected"))
+		return util.ErrReviewNotFound
+	}
+
+	query = "UPDATE work_logs SET work_description = ?, end_time = ?, duration = ? WHERE user_id = ? AND id = ?;"
+	_, err = tx.ExecContext(ctx, query, workLog.WorkDescription, workLog.EndTime, workLog.Duration, workLog.UserId, workLog.Id)
+	if err != nil {
+		return queryError(ctx, logger, "Exec ResolveWorkLogReview UpdateWorkLog", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return queryError(ctx, logger, "Commit ResolveWorkLogReview", err)
+	}
+
+	return nil
+}
@@ -2,70 +2,80 @@ package sqlite
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"log/slog"
 
 	"github.com/NerdBow/Grinders-API/internal/util"
 )
 
-func (db *SQLiteDB) AddUser(logger *slog.Logger, user util.User) error {
+func (db *SQLiteDB) AddUser(ctx context.Context, logger *slog.Logger, user util.User) error {
 	query := "INSERT INTO users (username, hash, creation_time) VALUES (?, ?, ?);"
-	result, err := db.Exec(query, user.Username, user.Hash, user.CreationTime)
+	result, err := db.ExecContext(ctx, query, user.Username, user.Hash, user.CreationTime)
+	if isUniqueViolation(err) {
+		logger.LogAttrs(ctx, slog.LevelInfo, "Exec AddUser", slog.String("err", err.Error()))
+		return util.ErrUsernameTaken
+	}
 	if err != nil {
-		logger.LogAttrs(context.Background(), slog.LevelError, "Exec AddUser", slog.String("err", err.Error()))
-		return util.ErrDatabase
+		return queryError(ctx, logger, "Exec AddUser", err)
 	}
 
 	n, err := result.RowsAffected()
 	if err != nil {
-		logger.LogAttrs(context.Background(), slog.LevelWarn, "RowsAffected AddUser", slog.String("err", err.Error()))
+		logger.LogAttrs(ctx, slog.LevelWarn, "RowsAffected AddUser", slog.String("err", err.Error()))
 	}
 	if n < 1 {
-		logger.LogAttrs(context.Background(), slog.LevelWarn, "RowsAffected AddUser", slog.String("err", "There were no rows affected"))
+		logger.LogAttrs(ctx, slog.LevelWarn, "RowsAffected AddUser", slog.String("err", "There were no rows affected"))
 	}
 
 	return nil
 }
 
-func (db *SQLiteDB) GetUser(logger *slog.Logger, userId uint64) (util.User, error) {
+func (db *SQLiteDB) GetUser(ctx context.Context, logger *slog.Logger, userId uint64) (util.User, error) {
 	query := "SELECT id, username, hash, creation_time FROM users WHERE id = ?;"
-	row := db.QueryRow(query, userId)
+	row := db.QueryRowContext(ctx, query, userId)
 
 	user := util.User{}
 	err := row.Scan(&user.Id, &user.Username, &user.Hash, &user.CreationTime)
+	if errors.Is(err, sql.ErrNoRows) {
+		logger.LogAttrs(ctx, slog.LevelInfo, "Scan GetUser", slog.String("err", err.Error()))
+		return user, util.ErrUserNotFound
+	}
 	if err != nil {
-		logger.LogAttrs(context.Background(), slog.LevelError, "Scan GetUser", slog.String("err", err.Error()))
-		return user, util.ErrDatabase
+		return user, queryError(ctx, logger, "Scan GetUser", err)
 	}
 	return user, nil
 }
 
-func (db *SQLiteDB) GetUserByUsername(logger *slog.Logger, username string) (util.User, error) {
+func (db *SQLiteDB) GetUserByUsername(ctx context.Context, logger *slog.Logger, username string) (util.User, error) {
 	query := "SELECT id, username, hash, creation_time FROM users WHERE username = ?;"
-	row := db.QueryRow(query, username)
+	row := db.QueryRowContext(ctx, query, username)
 
 	user := util.User{}
 	err := row.Scan(&user.Id, &user.Username, &user.Hash, &user.CreationTime)
+	if errors.Is(err, sql.ErrNoRows) {
+		logger.LogAttrs(ctx, slog.LevelInfo, "Scan GetUserByUsername", slog.String("err", err.Error()))
+		return user, util.ErrUserNotFound
+	}
 	if err != nil {
-		logger.LogAttrs(context.Background(), slog.LevelError, "Scan GetUserByUsername", slog.String("err", err.Error()))
-		return user, util.ErrDatabase
+		return user, queryError(ctx, logger, "Scan GetUserByUsername", err)
 	}
 	return user, nil
 }
 
-func (db *SQLiteDB) EditUsername(logger slog.Logger, userId uint64, newName string) error {
+func (db *SQLiteDB) EditUsername(ctx context.Context, logger *slog.Logger, userId uint64, newName string) error {
 	query := "UPDATE users SET username = ? WHERE id = ?;"
-	result, err := db.Exec(query, newName, userId)
+	result, err := db.ExecContext(ctx, query, newName, userId)
 	if err != nil {
-		logger.LogAttrs(context.Background(), slog.LevelError, "Exec EditUsername", slog.String("err", err.Error()))
-		return util.ErrDatabase
+		return queryError(ctx, logger, "Exec EditUsername", err)
 	}
 
 	n, err := result.RowsAffected()
 	if err != nil {
-		logger.LogAttrs(context.Background(), slog.LevelWarn, "RowsAffected EditUsername", slog.String("err", err.Error()))
+		logger.LogAttrs(ctx, slog.LevelWarn, "RowsAffected EditUsername", slog.String("err", err.Error()))
 	}
 	if n < 1 {
-		logger.LogAttrs(context.Background(), slog.LevelWarn, "RowsAffected EditUsername", slog.String("err", "There were no rows affected"))
+		logger.LogAttrs(ctx, slog.LevelWarn, "RowsAffected EditUsername", slog.String("err", "There were no rows affected"))
 	}
 
 	return nil
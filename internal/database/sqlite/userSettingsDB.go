@@ -0,0 +1,46 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+
+	"github.com/NerdBow/Grinders-API/internal/util"
+)
+
+func (db *SQLiteDB) GetUserSettings(ctx context.Context, logger *slog.Logger, userId uint64) (util.UserSettings, error) {
+	query := "SELECT user_id, time_zone FROM user_settings WHERE user_id = ?;"
+	row := db.QueryRowContext(ctx, query, userId)
+
+	settings := util.UserSettings{}
+	err := row.Scan(&settings.UserId, &settings.TimeZone)
+	if errors.Is(err, sql.ErrNoRows) {
+		return util.UserSettings{UserId: userId}, nil
+	}
+	if err != nil {
+		return settings, queryError(ctx, logger, "Scan GetUserSettings", err)
+	}
+	return settings, nil
+}
+
+func (db *SQLiteDB) SetUserSettings(ctx context.Context, logger *slog.Logger, settings util.UserSettings) error {
+	query := `INSERT INTO user_settings (user_id, time_zone) VALUES (?, ?)
+	ON CONFLICT(user_id) DO UPDATE SET time_zone = excluded.time_zone;`
+
+	result, err := db.ExecContext(ctx, query, settings.UserId, settings.TimeZone)
+	if err != nil {
+		return queryError(ctx, logger, "Exec SetUserSettings", err)
+	}
+
+	n, err := result.RowsAffected()
+	if err != nil {
+		logger.LogAttrs(ctx, slog.LevelWarn, "RowsAffected SetUserSettings", slog.String("err", err.Error()))
+	}
+
+	if n != 1 {
+		logger.LogAttrs(ctx, slog.LevelWarn, "RowsAffected SetUserSettings", slog.String("err", "There were no rows affected"))
+	}
+
+	return nil
+}
@@ -0,0 +1,446 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/NerdBow/Grinders-API/internal/util"
+)
+
+const workLogColumns = "id, task_id, objective, work_description, is_complete, start_time, duration, end_time, user_id"
+
+// scanner is the Scan method shared by sql.Row and sql.Rows.
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanWorkLog(row scanner) (util.WorkLog, error) {
+	workLog := util.WorkLog{}
+	err := row.Scan(&workLog.Id, &workLog.TaskId, &workLog.Objective, &workLog.WorkDescription, &workLog.IsComplete, &workLog.StartTime, &workLog.Duration, &workLog.EndTime, &workLog.UserId)
+	return workLog, err
+}
+
+func (db *SQLiteDB) AddWorkLog(ctx context.Context, logger *slog.Logger, workLog util.WorkLog) (uint64, error) {
+	query := `INSERT INTO work_logs
+	(task_id, objective, work_description, is_complete, start_time, duration, end_time, user_id) VALUES
+	(?, ?, ?, ?, ?, ?, ?, ?);`
+
+	result, err := db.ExecContext(ctx, query, workLog.TaskId, workLog.Objective, workLog.WorkDescription, workLog.IsComplete, workLog.StartTime, workLog.Duration, workLog.EndTime, workLog.UserId)
+	if isUniqueViolation(err) {
+		logger.LogAttrs(ctx, slog.LevelInfo, "Exec AddWorkLog", slog.String("err", err.Error()))
+		return 0, util.ErrWorkLogConflict
+	}
+	if err != nil {
+		return 0, queryError(ctx, logger, "Exec AddWorkLog", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, queryError(ctx, logger, "LastInsertId AddWorkLog", err)
+	}
+
+	return uint64(id), nil
+}
+
+func (db *SQLiteDB) SwitchWorkLog(ctx context.Context, logger *slog.Logger, stopped util.WorkLog, pause util.Pause, workLog util.WorkLog) (uint64, error) {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, queryError(ctx, logger, "Begin SwitchWorkLog", err)
+	}
+	defer tx.Rollback()
+
+	if pause.Id != 0 {
+		query := "UPDATE pauses SET end_time = ?, duration = ? WHERE id = ? AND end_time = ?;"
+		_, err = tx.ExecContext(ctx, query, pause.EndTime, pause.Duration, pause.Id, time.Time{})
+		if err != nil {
+			return 0, queryError(ctx, logger, "Exec SwitchWorkLog EndPause", err)
+		}
+	}
+
+	query := `UPDATE work_logs SET work_description = ?, is_complete = ?, end_time = ?, duration = ?
+	WHERE user_id = ? AND id = ? AND is_complete = ?;`
+	result, err := tx.ExecContext(ctx, query, stopped.WorkDescription, true, stopped.EndTime, stopped.Duration, stopped.UserId, stopped.Id, false)
+	if err != nil {
+		return 0, queryError(ctx, logger, "Exec SwitchWorkLog StopWorkLog", err)
+	}
+
+	n, err := result.RowsAffected()
+	if err != nil {
+		logger.LogAttrs(ctx, slog.LevelWarn, "RowsAffected SwitchWorkLog", slog.String("err", err.Error()))
+	}
+	if n != 1 {
+		logger.LogAttrs(ctx, slog.LevelInfo, "RowsAffected SwitchWorkLog", slog.String("err", "Stopped work log is no longer running"))
+		return 0, util.ErrWorkLogConflict
+	}
+
+	query = `INSERT INTO work_logs
+	(task_id, objective, work_description, is_complete, start_time, duration, end_time, user_id) VALUES
+	(?, ?, ?, ?, ?, ?, ?, ?);`
+	result, err = tx.ExecContext(ctx, query, workLog.TaskId, workLog.Objective, workLog.WorkDescription, workLog.IsComplete, workLog.StartTime, workLog.Duration, workLog.EndTime, workLog.UserId)
+	if isUniqueViolation(err) {
+		logger.LogAttrs(ctx, slog.LevelInfo, "Exec SwitchWorkLog AddWorkLog", slog.String("err", err.Error()))
+		return 0, util.ErrWorkLogConflict
+	}
+	if err != nil {
+		return 0, queryError(ctx, logger, "Exec SwitchWorkLog AddWorkLog", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, queryError(ctx, logger, "LastInsertId SwitchWorkLog", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return 0, queryError(ctx, logger, "Commit SwitchWorkLog", err)
+	}
+
+	return uint64(id), nil
+}
+
+func (db *SQLiteDB) AddCompletedWorkLog(ctx context.Context, logger *slog.Logger, workLog util.WorkLog) (uint64, []util.WorkLog, error) {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, nil, queryError(ctx, logger, "Begin AddCompletedWorkLog", err)
+	}
+	defer tx.Rollback()
+
+	query := `SELECT ` + workLogColumns + ` FROM work_logs
+	WHERE user_id = ? AND start_time < ? AND (end_time > ? OR is_complete = ?)
+	ORDER BY start_time ASC;`
+	rows, err := tx.QueryContext(ctx, query, workLog.UserId, workLog.EndTime, workLog.StartTime, false)
+	if err != nil {
+		return 0, nil, queryError(ctx, logger, "Query AddCompletedWorkLog", err)
+	}
+	defer rows.Close()
+
+	overlaps := make([]util.WorkLog, 0)
+	for rows.Next() {
+		overlap, err := scanWorkLog(rows)
+		if err != nil {
+			return 0, nil, queryError(ctx, logger, "Scan AddCompletedWorkLog", err)
+		}
+		overlaps = append(overlaps, overlap)
+	}
+	if err = rows.Err(); err != nil {
+		return 0, nil, queryError(ctx, logger, "Rows AddCompletedWorkLog", err)
+	}
+	if len(overlaps) > 0 {
+		return 0, overlaps, nil
+	}
+
+	query = `INSERT INTO work_logs
+	(task_id, objective, work_description, is_complete, start_time, duration, end_time, user_id) VALUES
+	(?, ?, ?, ?, ?, ?, ?, ?);`
+	result, err := tx.ExecContext(ctx, query, workLog.TaskId, workLog.Objective, workLog.WorkDescription, true, workLog.StartTime, workLog.Duration, workLog.EndTime, workLog.UserId)
+	if err != nil {
+		return 0, nil, queryError(ctx, logger, "Exec AddCompletedWorkLog", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, nil, queryError(ctx, logger, "LastInsertId AddCompletedWorkLog", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return 0, nil, queryError(ctx, logger, "Commit AddCompletedWorkLog", err)
+	}
+
+	return uint64(id), nil, nil
+}
+
+func (db *SQLiteDB) GetRunningWorkLog(ctx context.Context, logger *slog.Logger, userId uint64) (util.WorkLog, error) {
+	query := "SELECT " + workLogColumns + " FROM work_logs WHERE user_id = ? AND is_complete = ?;"
+	row := db.QueryRowContext(ctx, query, userId, false)
+
+	workLog, err := scanWorkLog(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return workLog, util.ErrWorkLogNotFound
+	}
+	if err != nil {
+		return workLog, queryError(ctx, logger, "Scan GetRunningWorkLog", err)
+	}
+	return workLog, nil
+}
+
+func (db *SQLiteDB) GetWorkLog(ctx context.Context, logger *slog.Logger, workLogId uint64, userId uint64) (util.WorkLog, error) {
+	query := "SELECT " + workLogColumns + " FROM work_logs WHERE id = ? AND user_id = ?;"
+	row := db.QueryRowContext(ctx, query, workLogId, userId)
+
+	workLog, err := scanWorkLog(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return workLog, util.ErrWorkLogNotFound
+	}
+	if err != nil {
+		return workLog, queryError(ctx, logger, "Scan GetWorkLog", err)
+	}
+	return workLog, nil
+}
+
+func (db *SQLiteDB) QueryWorkLogs(ctx context.Context, logger *slog.Logger, querySettings util.WorkLogQuerySettings) ([]util.WorkLog, error) {
+	query := "SELECT " + workLogColumns + " FROM work_logs WHERE user_id = ?"
+
+	params := make([]any, 0, 5)
+	params = append(params, querySettings.UserId)
+
+	if querySettings.TaskId != 0 {
+		query += " AND task_id = ?"
+		params = append(params, querySettings.TaskId)
+	}
+	if !querySettings.From.IsZero() {
+		query += " AND start_time >= ?"
+		params = append(params, querySettings.From)
+	}
+	if !querySettings.To.IsZero() {
+		query += " AND start_time < ?"
+		params = append(params, querySettings.To)
+	}
+	query += " ORDER BY start_time ASC LIMIT ? OFFSET ?;"
+	params = append(params, PAGE_SIZE, (querySettings.Page-1)*PAGE_SIZE)
+
+	logger.LogAttrs(ctx, slog.LevelDebug, "SQL Query QueryWorkLogs", slog.String("query", query))
+	rows, err := db.QueryContext(ctx, query, params...)
+	if err != nil {
+		return nil, queryError(ctx, logger, "Query QueryWorkLogs", err)
+	}
+	defer rows.Close()
+
+	workLogs := make([]util.WorkLog, 0, PAGE_SIZE)
+	for rows.Next() {
+		workLog, err := scanWorkLog(rows)
+		if err != nil {
+			return nil, queryError(ctx, logger, "Scan QueryWorkLogs", err)
+		}
+		workLogs = append(workLogs, workLog)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, queryError(ctx, logger, "Rows QueryWorkLogs", err)
+	}
+
+	return workLogs, nil
+}
+
+func (db *SQLiteDB) StopWorkLog(ctx context.Context, logger *slog.Logger, workLog util.WorkLog) error {
+	query := `UPDATE work_logs SET work_description = ?, is_complete = ?, end_time = ?, duration = ?
+	WHERE user_id = ? AND id = ? AND is_complete = ?;`
+
+	result, err := db.ExecContext(ctx, query, workLog.WorkDescription, true, workLog.EndTime, workLog.Duration, workLog.UserId, workLog.Id, false)
+	if err != nil {
+		return queryError(ctx, logger, "Exec StopWorkLog", err)
+	}
+
+	n, err := result.RowsAffected()
+	if err != nil {
+		logger.LogAttrs(ctx, slog.LevelWarn, "RowsAffected StopWorkLog", slog.String("err", err.Error()))
+	}
+
+	if n != 1 {
+		logger.LogAttrs(ctx, slog.LevelWarn, "RowsAffected StopWorkLog", slog.String("err", "There were no rows affected"))
+		return util.ErrWorkLogNotFound
+	}
+
+	return nil
+}
+
+func (db *SQLiteDB) SplitWorkLog(ctx context.Context, logger *slog.Logger, first util.WorkLog, second util.WorkLog, firstPauses []util.Pause, secondPauses []util.Pause) (uint64, error) {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, queryError(ctx, logger, "Begin SplitWorkLog", err)
+	}
+	defer tx.Rollback()
+
+	query := "UPDATE work_logs SET end_time = ?, duration = ? WHERE user_id = ? AND id = ? AND is_complete = ?;"
+	result, err := tx.ExecContext(ctx, query, first.EndTime, first.Duration, first.UserId, first.Id, true)
+	if err != nil {
+		return 0, queryError(ctx, logger, "Exec SplitWorkLog UpdateFirst", err)
+	}
+
+	n, err := result.RowsAffected()
+	if err != nil {
+		logger.LogAttrs(ctx, slog.LevelWarn, "RowsAffected SplitWorkLog", slog.String("err", err.Error()))
+	}
+	if n != 1 {
+		logger.LogAttrs(ctx, slog.LevelWarn, "RowsAffected SplitWorkLog", slog.String("err", "There were no rows affected"))
+		return 0, util.ErrWorkLogNotFound
+	}
+
+	query = `INSERT INTO work_logs
+	(task_id, objective, work_description, is_complete, start_time, duration, end_time, user_id) VALUES
+	(?, ?, ?, ?, ?, ?, ?, ?);`
+	result, err = tx.ExecContext(ctx, query, second.TaskId, second.Objective, second.WorkDescription, true, second.StartTime, second.Duration, second.EndTime, second.UserId)
+	if err != nil {
+		return 0, queryError(ctx, logger, "Exec SplitWorkLog AddSecond", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, queryError(ctx, logger, "LastInsertId SplitWorkLog", err)
+	}
+
+	err = savePauses(ctx, tx, first.Id, firstPauses)
+	if err != nil {
+		return 0, queryError(ctx, logger, "Exec SplitWorkLog SaveFirstPauses", err)
+	}
+	err = savePauses(ctx, tx, uint64(id), secondPauses)
+	if err != nil {
+		return 0, queryError(ctx, logger, "Exec SplitWorkLog SaveSecondPauses", err)
+	}
+
+	query = "UPDATE breaks SET work_log_id = ? WHERE work_log_id = ? AND start_time >= ?;"
+	_, err = tx.ExecContext(ctx, query, id, first.Id, second.StartTime)
+	if err != nil {
+		return 0, queryError(ctx, logger, "Exec SplitWorkLog MoveBreaks", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return 0, queryError(ctx, logger, "Commit SplitWorkLog", err)
+	}
+
+	return uint64(id), nil
+}
+
+func (db *SQLiteDB) MergeWorkLogs(ctx context.Context, logger *slog.Logger, merged util.WorkLog, otherIds []uint64, gapPauses []util.Pause) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return queryError(ctx, logger, "Begin MergeWorkLogs", err)
+	}
+	defer tx.Rollback()
+
+	query := "SELECT COUNT(*) FROM work_logs WHERE user_id = ? AND start_time >= ? AND start_time < ?;"
+	count := 0
+	err = tx.QueryRowContext(ctx, query, merged.UserId, merged.StartTime, merged.EndTime).Scan(&count)
+	if err != nil {
+		return queryError(ctx, logger, "Scan MergeWorkLogs", err)
+	}
+	if count != len(otherIds)+1 {
+		logger.LogAttrs(ctx, slog.LevelInfo, "MergeWorkLogs", slog.Int("found", count), slog.Int("expected", len(otherIds)+1))
+		return util.ErrMergeNotAdjacent
+	}
+
+	in := "(?" + strings.Repeat(", ?", len(otherIds)-1) + ")"
+	params := make([]any, 0, len(otherIds)+2)
+	params = append(params, merged.Id)
+	for _, id := range otherIds {
+		params = append(params, id)
+	}
+
+	// The merged work log would lose the automatic stop times the reviews are corrected against.
+	all := "(?" + strings.Repeat(", ?", len(otherIds)) + ")"
+	rows, err := tx.QueryContext(ctx, "SELECT work_log_id FROM work_log_reviews WHERE work_log_id IN "+all+" ORDER BY work_log_id;", params...)
+	if err != nil {
+		return queryError(ctx, logger, "Query MergeWorkLogs Reviews", err)
+	}
+	defer rows.Close()
+
+	reviewed := make([]uint64, 0)
+	for rows.Next() {
+		id := uint64(0)
+		if err := rows.Scan(&id); err != nil {
+			return queryError(ctx, logger, "Scan MergeWorkLogs Reviews", err)
+		}
+		reviewed = append(reviewed, id)
+	}
+	if err = rows.Err(); err != nil {
+		return queryError(ctx, logger, "Rows MergeWorkLogs Reviews", err)
+	}
+	if len(reviewed) > 0 {
+		return &util.ConflictError{Err: util.ErrMergeReviewPending, Conflict: reviewed}
+	}
+
+	_, err = tx.ExecContext(ctx, "UPDATE pauses SET work_log_id = ? WHERE work_log_id IN "+in+";", params...)
+	if err != nil {
+		return queryError(ctx, logger, "Exec MergeWorkLogs MovePauses", err)
+	}
+	_, err = tx.ExecContext(ctx, "UPDATE breaks SET work_log_id = ? WHERE work_log_id IN "+in+";", params...)
+	if err != nil {
+		return queryError(ctx, logger, "Exec MergeWorkLogs MoveBreaks", err)
+	}
+
+	err = savePauses(ctx, tx, merged.Id, gapPauses)
+	if err != nil {
+		return queryError(ctx, logger, "Exec MergeWorkLogs SaveGapPauses", err)
+	}
+
+	query = `UPDATE work_logs SET objective = ?, work_description = ?, end_time = ?, duration = ?
+	WHERE user_id = ? AND id = ? AND is_complete = ?;`
+	result, err := tx.ExecContext(ctx, query, merged.Objective, merged.WorkDescription, merged.EndTime, merged.Duration, merged.UserId, merged.Id, true)
+	if err != nil {
+		return queryError(ctx, logger, "Exec MergeWorkLogs UpdateMerged", err)
+	}
+
+	n, err := result.RowsAffected()
+	if err != nil {
+		logger.LogAttrs(ctx, slog.LevelWarn, "RowsAffected MergeWorkLogs", slog.String("err", err.Error()))
+	}
+	if n != 1 {
+		logger.LogAttrs(ctx, slog.LevelWarn, "RowsAffected MergeWorkLogs", slog.String("err", "There were no rows affected"))
+		return util.ErrWorkLogNotFound
+	}
+
+	params = append(params[1:], merged.UserId, true)
+	result, err = tx.ExecContext(ctx, "DELETE FROM work_logs WHERE id IN "+in+" AND user_id = ? AND is_complete = ?;", params...)
+	if err != nil {
+		return queryError(ctx, logger, "Exec MergeWorkLogs DeleteOthers", err)
+	}
+
+	n, err = result.RowsAffected()
+	if err != nil {
+		logger.LogAttrs(ctx, slog.LevelWarn, "RowsAffected MergeWorkLogs", slog.String("err", err.Error()))
+	}
+	if n != int64(len(otherIds)) {
+		logger.LogAttrs(ctx, slog.LevelWarn, "RowsAffected MergeWorkLogs", slog.String("err", "Not all merged work logs were deleted"))
+		return util.ErrWorkLogNotFound
+	}
+
+	if err = tx.Commit(); err != nil {
+		return queryError(ctx, logger, "Commit MergeWorkLogs", err)
+	}
+
+	return nil
+}
+
+// savePauses saves the pauses on the work log within tx. Pauses with an id of 0 are created, the others are updated.
+func savePauses(ctx context.Context, tx *sql.Tx, workLogId uint64, pauses []util.Pause) error {
+	for _, pause := range pauses {
+		var err error
+		if pause.Id == 0 {
+			query := "INSERT INTO pauses (start_time, duration, end_time, work_log_id) VALUES (?, ?, ?, ?);"
+			_, err = tx.ExecContext(ctx, query, pause.StartTime, pause.Duration, pause.EndTime, workLogId)
+		} else {
+			query := "UPDATE pauses SET start_time = ?, duration = ?, end_time = ?, work_log_id = ? WHERE id = ?;"
+			_, err = tx.ExecContext(ctx, query, pause.StartTime, pause.Duration, pause.EndTime, workLogId, pause.Id)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (db *SQLiteDB) GetWorkLogsBetween(ctx context.Context, logger *slog.Logger, userId uint64, from time.Time, to time.Time) ([]util.WorkLog, error) {
+	query := "SELECT " + workLogColumns + ` FROM work_logs
+	WHERE user_id = ? AND start_time < ? AND (end_time > ? OR is_complete = ?)
+	ORDER BY start_time ASC;`
+	rows, err := db.QueryContext(ctx, query, userId, to, from, false)
+	if err != nil {
+		return nil, queryError(ctx, logger, "Query GetWorkLogsBetween", err)
+	}
+	defer rows.Close()
+
+	workLogs := make([]util.WorkLog, 0, 10)
+	for rows.Next() {
+		workLog, err := scanWorkLog(rows)
+		if err != nil {
+			return nil, queryError(ctx, logger, "Scan GetWorkLogsBetween", err)
+		}
+		workLogs = append(workLogs, workLog)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, queryError(ctx, logger, "Rows GetWorkLogsBetween", err)
+	}
+
+	return workLogs, nil
+}
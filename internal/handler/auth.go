@@ -0,0 +1,148 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+
+	"github.com/NerdBow/Grinders-API/internal/auth"
+	"github.com/NerdBow/Grinders-API/internal/service"
+	"github.com/NerdBow/Grinders-API/internal/util"
+)
+
+type credentials struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// RegisterHandler creates a new user from the username and password in the request body.
+func RegisterHandler(s *service.AuthService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		body := credentials{}
+		if err := decodeJSON(w, r, &body); err != nil {
+			util.WriteProblem(w, r, fmt.Errorf("%w: body must be a JSON object with a username and password", util.ErrMalformedBody))
+			return
+		}
+
+		err := s.RegisterNewUser(r.Context(), util.LoggerFromContext(r.Context()), body.Username, body.Password)
+		if err != nil {
+			util.WriteProblem(w, r, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusCreated)
+	}
+}
+
+// LoginHandler creates a new session for the username and password in the request body and returns its tokens.
+func LoginHandler(s *service.AuthService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		body := credentials{}
+		if err := decodeJSON(w, r, &body); err != nil {
+			util.WriteProblem(w, r, fmt.Errorf("%w: body must be a JSON object with a username and password", util.ErrMalformedBody))
+			return
+		}
+
+		tokens, err := s.Login(r.Context(), util.LoggerFromContext(r.Context()), body.Username, body.Password, clientIp(r))
+		if errors.Is(err, util.ErrUserNotFound) || errors.Is(err, util.ErrHashMismatch) {
+			util.WriteProblem(w, r, util.ErrInvalidCredentials)
+			return
+		}
+		if err != nil {
+			util.WriteProblem(w, r, err)
+			return
+		}
+
+		writeJSON(w, http.StatusOK, tokens)
+	}
+}
+
+// RefreshHandler exchanges the tokens in the request body for a new pair of tokens.
+// The access token may be expired, it is only used to identify the user of the session.
+func RefreshHandler(s *service.AuthService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userId, refreshToken, ok := decodeTokens(w, r)
+		if !ok {
+			return
+		}
+
+		tokens, err := s.Refresh(r.Context(), util.LoggerFromContext(r.Context()), refreshToken, userId)
+		if err != nil {
+			util.WriteProblem(w, r, err)
+			return
+		}
+
+		writeJSON(w, http.StatusOK, tokens)
+	}
+}
+
+// LogoutHandler deletes the session of the refresh token in the request body.
+func LogoutHandler(s *service.AuthService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userId, refreshToken, ok := decodeTokens(w, r)
+		if !ok {
+			return
+		}
+
+		err := s.Logout(r.Context(), util.LoggerFromContext(r.Context()), refreshToken, userId)
+		if err != nil {
+			util.WriteProblem(w, r, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// ClearUserLockoutHandler removes the lockout and failed logins of the username in the path.
+func ClearUserLockoutHandler(s *service.AuthService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		err := s.ClearLoginAttempts(r.Context(), util.LoggerFromContext(r.Context()), service.USER_ATTEMPT_PREFIX, r.PathValue("username"))
+		if err != nil {
+			util.WriteProblem(w, r, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// ClearIpLockoutHandler removes the failed logins of the client ip in the path.
+func ClearIpLockoutHandler(s *service.AuthService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		err := s.ClearLoginAttempts(r.Context(), util.LoggerFromContext(r.Context()), service.IP_ATTEMPT_PREFIX, r.PathValue("ip"))
+		if err != nil {
+			util.WriteProblem(w, r, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// clientIp returns the ip address of the client connection.
+// Forwarding headers are ignored since they can be set by the client to dodge the login limits.
+func clientIp(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// decodeTokens decodes a util.Tokens body and returns the user id of the access token and the refresh token.
+// If the body is invalid a problem response is written and ok is false.
+func decodeTokens(w http.ResponseWriter, r *http.Request) (userId uint64, refreshToken string, ok bool) {
+	body := util.Tokens{}
+	if err := decodeJSON(w, r, &body); err != nil || body.Access == "" || body.Refresh == "" {
+		util.WriteProblem(w, r, fmt.Errorf("%w: body must be a JSON object with an access and refresh token", util.ErrMalformedBody))
+		return 0, "", false
+	}
+
+	accessToken, err := auth.ParseExpiredToken(body.Access)
+	if err != nil {
+		util.WriteProblem(w, r, util.ErrMalformedToken)
+		return 0, "", false
+	}
+
+	return accessToken.Sub, body.Refresh, true
+}
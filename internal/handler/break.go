@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/NerdBow/Grinders-API/internal/auth"
+	"github.com/NerdBow/Grinders-API/internal/service"
+	"github.com/NerdBow/Grinders-API/internal/util"
+)
+
+// StartBreakHandler starts a break after the work log with the id in the path.
+func StartBreakHandler(s *service.BreakService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userId := auth.UserIdFromContext(r.Context())
+		workLogId, ok := pathId(w, r)
+		if !ok {
+			return
+		}
+
+		brk, err := s.StartBreak(r.Context(), util.LoggerFromContext(r.Context()), userId, workLogId)
+		if err != nil {
+			util.WriteProblem(w, r, err)
+			return
+		}
+		writeJSON(w, http.StatusCreated, brk)
+	}
+}
+
+// EndBreakHandler ends the break with the id in the path.
+func EndBreakHandler(s *service.BreakService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userId := auth.UserIdFromContext(r.Context())
+		breakId, ok := pathId(w, r)
+		if !ok {
+			return
+		}
+
+		brk, err := s.EndBreak(r.Context(), util.LoggerFromContext(r.Context()), userId, breakId)
+		if err != nil {
+			util.WriteProblem(w, r, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, brk)
+	}
+}
+
+// GetDailyBreaksHandler returns the total break time per day for the query parameters
+// from and to (YYYY-MM-DD dates, both included) in the time zone tz. Both dates default to today.
+func GetDailyBreaksHandler(s *service.BreakService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userId := auth.UserIdFromContext(r.Context())
+
+		query := r.URL.Query()
+		loc, locErr := queryLocation(query, "tz")
+		from, fromErr := queryDate(query, "from")
+		to, toErr := queryDate(query, "to")
+		if err := errors.Join(locErr, fromErr, toErr); err != nil {
+			util.WriteProblem(w, r, err)
+			return
+		}
+
+		today := time.Now().In(loc)
+		if from.IsZero() {
+			from = today
+		}
+		if to.IsZero() {
+			to = today
+		}
+
+		totals, err := s.GetDailyBreakTotals(r.Context(), util.LoggerFromContext(r.Context()), userId, from, to, loc)
+		if err != nil {
+			util.WriteProblem(w, r, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, totals)
+	}
+}
@@ -0,0 +1,116 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/NerdBow/Grinders-API/internal/auth"
+	"github.com/NerdBow/Grinders-API/internal/service"
+	"github.com/NerdBow/Grinders-API/internal/util"
+)
+
+type categoryName struct {
+	Name string `json:"name"`
+}
+
+// GetCategoriesHandler returns all categories of the user.
+// If the prefix query parameter is given only the categories starting with it are returned.
+func GetCategoriesHandler(s *service.CategoryService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userId := auth.UserIdFromContext(r.Context())
+
+		query := r.URL.Query()
+		if query.Has("prefix") {
+			categories, err := s.QueryCategory(r.Context(), util.LoggerFromContext(r.Context()), userId, query.Get("prefix"))
+			if err != nil {
+				util.WriteProblem(w, r, err)
+				return
+			}
+			writeJSON(w, http.StatusOK, categories)
+			return
+		}
+
+		categories, err := s.GetAllCategories(r.Context(), util.LoggerFromContext(r.Context()), userId)
+		if err != nil {
+			util.WriteProblem(w, r, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, categories)
+	}
+}
+
+// GetCategoryHandler returns the category of the user with the name in the path.
+func GetCategoryHandler(s *service.CategoryService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userId := auth.UserIdFromContext(r.Context())
+
+		category, err := s.GetCategory(r.Context(), util.LoggerFromContext(r.Context()), userId, r.PathValue("name"))
+		if err != nil {
+			util.WriteProblem(w, r, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, category)
+	}
+}
+
+// CreateCategoryHandler creates a category with the name in the request body.
+func CreateCategoryHandler(s *service.CategoryService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userId := auth.UserIdFromContext(r.Context())
+
+		body := categoryName{}
+		if err := decodeJSON(w, r, &body); err != nil {
+			util.WriteProblem(w, r, fmt.Errorf("%w: body must be a JSON object with a name", util.ErrMalformedBody))
+			return
+		}
+
+		category, err := s.CreateCategory(r.Context(), util.LoggerFromContext(r.Context()), userId, body.Name)
+		if err != nil {
+			util.WriteProblem(w, r, err)
+			return
+		}
+		writeJSON(w, http.StatusCreated, category)
+	}
+}
+
+// RenameCategoryHandler changes the name of the category with the id in the path to the name in the request body.
+func RenameCategoryHandler(s *service.CategoryService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userId := auth.UserIdFromContext(r.Context())
+		categoryId, ok := pathId(w, r)
+		if !ok {
+			return
+		}
+
+		body := categoryName{}
+		if err := decodeJSON(w, r, &body); err != nil {
+			util.WriteProblem(w, r, fmt.Errorf("%w: body must be a JSON object with a name", util.ErrMalformedBody))
+			return
+		}
+
+		category, err := s.ChangeName(r.Context(), util.LoggerFromContext(r.Context()), userId, categoryId, body.Name)
+		if err != nil {
+			util.WriteProblem(w, r, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, category)
+	}
+}
+
+// DeleteCategoryHandler deletes the category with the id in the path.
+func DeleteCategoryHandler(s *service.CategoryService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userId := auth.UserIdFromContext(r.Context())
+		categoryId, ok := pathId(w, r)
+		if !ok {
+			return
+		}
+
+		err := s.DeleteCategory(r.Context(), util.LoggerFromContext(r.Context()), userId, categoryId)
+		if err != nil {
+			util.WriteProblem(w, r, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,144 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/NerdBow/Grinders-API/internal/auth"
+	"github.com/NerdBow/Grinders-API/internal/service"
+	"github.com/NerdBow/Grinders-API/internal/util"
+)
+
+type goalBody struct {
+	CategoryId uint64        `json:"categoryId"`
+	Value      int64         `json:"value"`
+	Type       util.GoalType `json:"type"`
+}
+
+const goalBodyProblem = "body must be a JSON object with a categoryId, value and type such as dailyTime, weeklyTasks or monthlyDeadlines"
+
+// CreateGoalHandler creates a goal with the categoryId, value and type in the request body.
+func CreateGoalHandler(s *service.GoalService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userId := auth.UserIdFromContext(r.Context())
+
+		body := goalBody{}
+		if err := decodeJSON(w, r, &body); err != nil {
+			util.WriteProblem(w, r, fmt.Errorf("%w: %s", util.ErrMalformedBody, goalBodyProblem))
+			return
+		}
+
+		goal, err := s.CreateGoal(r.Context(), util.LoggerFromContext(r.Context()), userId, util.Goal{CategoryId: body.CategoryId, Value: body.Value, Type: body.Type})
+		if err != nil {
+			util.WriteProblem(w, r, err)
+			return
+		}
+		writeJSON(w, http.StatusCreated, goal)
+	}
+}
+
+// GetGoalsHandler returns all of the user's goals.
+func GetGoalsHandler(s *service.GoalService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userId := auth.UserIdFromContext(r.Context())
+
+		goals, err := s.GetAllGoals(r.Context(), util.LoggerFromContext(r.Context()), userId)
+		if err != nil {
+			util.WriteProblem(w, r, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, goals)
+	}
+}
+
+// GetGoalHandler returns the goal with the id in the path.
+func GetGoalHandler(s *service.GoalService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userId := auth.UserIdFromContext(r.Context())
+		goalId, ok := pathId(w, r)
+		if !ok {
+			return
+		}
+
+		goal, err := s.GetGoal(r.Context(), util.LoggerFromContext(r.Context()), userId, goalId)
+		if err != nil {
+			util.WriteProblem(w, r, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, goal)
+	}
+}
+
+// EditGoalHandler changes the fields given in the request body of the goal with the id in the path.
+func EditGoalHandler(s *service.GoalService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userId := auth.UserIdFromContext(r.Context())
+		goalId, ok := pathId(w, r)
+		if !ok {
+			return
+		}
+
+		body := goalBody{}
+		if err := decodeJSON(w, r, &body); err != nil {
+			util.WriteProblem(w, r, fmt.Errorf("%w: %s", util.ErrMalformedBody, goalBodyProblem))
+			return
+		}
+
+		goal, err := s.EditGoal(r.Context(), util.LoggerFromContext(r.Context()), userId, util.Goal{Id: goalId, CategoryId: body.CategoryId, Value: body.Value, Type: body.Type})
+		if err != nil {
+			util.WriteProblem(w, r, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, goal)
+	}
+}
+
+// DeleteGoalHandler deletes the goal with the id in the path.
+func DeleteGoalHandler(s *service.GoalService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userId := auth.UserIdFromContext(r.Context())
+		goalId, ok := pathId(w, r)
+		if !ok {
+			return
+		}
+
+		err := s.DeleteGoal(r.Context(), util.LoggerFromContext(r.Context()), userId, goalId)
+		if err != nil {
+			util.WriteProblem(w, r, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// GetGoalProgressHandler returns the progress of the current period of the goal with the id in the path.
+func GetGoalProgressHandler(s *service.GoalService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userId := auth.UserIdFromContext(r.Context())
+		goalId, ok := pathId(w, r)
+		if !ok {
+			return
+		}
+
+		progress, err := s.GetProgress(r.Context(), util.LoggerFromContext(r.Context()), userId, goalId)
+		if err != nil {
+			util.WriteProblem(w, r, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, progress)
+	}
+}
+
+// GetAllGoalProgressHandler returns the progress of the current period of every goal of the user.
+func GetAllGoalProgressHandler(s *service.GoalService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userId := auth.UserIdFromContext(r.Context())
+
+		progresses, err := s.GetAllProgress(r.Context(), util.LoggerFromContext(r.Context()), userId)
+		if err != nil {
+			util.WriteProblem(w, r, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, progresses)
+	}
+}
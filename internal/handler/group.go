@@ -0,0 +1,474 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/NerdBow/Grinders-API/internal/auth"
+	"github.com/NerdBow/Grinders-API/internal/service"
+	"github.com/NerdBow/Grinders-API/internal/util"
+)
+
+type groupName struct {
+	Name string `json:"name"`
+}
+
+type groupMemberBody struct {
+	Username string `json:"username"`
+}
+
+type roleBody struct {
+	Role util.GroupRole `json:"role"`
+}
+
+type transferBody struct {
+	UserId uint64 `json:"userId"`
+}
+
+// CreateGroupHandler creates a group owned by the user with the name in the request body.
+func CreateGroupHandler(s *service.GroupService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userId := auth.UserIdFromContext(r.Context())
+
+		body := groupName{}
+		if err := decodeJSON(w, r, &body); err != nil {
+			util.WriteProblem(w, r, fmt.Errorf("%w: body must be a JSON object with a name", util.ErrMalformedBody))
+			return
+		}
+
+		group, err := s.CreateGroup(r.Context(), util.LoggerFromContext(r.Context()), userId, body.Name)
+		if err != nil {
+			util.WriteProblem(w, r, err)
+			return
+		}
+		writeJSON(w, http.StatusCreated, group)
+	}
+}
+
+// GetGroupsHandler returns all groups the user is a member of.
+func GetGroupsHandler(s *service.GroupService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userId := auth.UserIdFromContext(r.Context())
+
+		groups, err := s.GetAllGroups(r.Context(), util.LoggerFromContext(r.Context()), userId)
+		if err != nil {
+			util.WriteProblem(w, r, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, groups)
+	}
+}
+
+// GetGroupHandler returns the group with the id in the path.
+func GetGroupHandler(s *service.GroupService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userId := auth.UserIdFromContext(r.Context())
+		groupId, ok := pathId(w, r)
+		if !ok {
+			return
+		}
+
+		group, err := s.GetGroup(r.Context(), util.LoggerFromContext(r.Context()), userId, groupId)
+		if err != nil {
+			util.WriteProblem(w, r, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, group)
+	}
+}
+
+// RenameGroupHandler changes the name of the group with the id in the path to the name in the request body.
+func RenameGroupHandler(s *service.GroupService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userId := auth.UserIdFromContext(r.Context())
+		groupId, ok := pathId(w, r)
+		if !ok {
+			return
+		}
+
+		body := groupName{}
+		if err := decodeJSON(w, r, &body); err != nil {
+			util.WriteProblem(w, r, fmt.Errorf("%w: body must be a JSON object with a name", util.ErrMalformedBody))
+			return
+		}
+
+		group, err := s.ChangeName(r.Context(), util.LoggerFromContext(r.Context()), userId, groupId, body.Name)
+		if err != nil {
+			util.WriteProblem(w, r, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, group)
+	}
+}
+
+// DeleteGroupHandler deletes the group with the id in the path.
+func DeleteGroupHandler(s *service.GroupService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userId := auth.UserIdFromContext(r.Context())
+		groupId, ok := pathId(w, r)
+		if !ok {
+			return
+		}
+
+		err := s.DeleteGroup(r.Context(), util.LoggerFromContext(r.Context()), userId, groupId)
+		if err != nil {
+			util.WriteProblem(w, r, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// GetGroupMembersHandler returns the members of the group with the id in the path.
+func GetGroupMembersHandler(s *service.GroupService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userId := auth.UserIdFromContext(r.Context())
+		groupId, ok := pathId(w, r)
+		if !ok {
+			return
+		}
+
+		members, err := s.GetMembers(r.Context(), util.LoggerFromContext(r.Context()), userId, groupId)
+		if err != nil {
+			util.WriteProblem(w, r, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, members)
+	}
+}
+
+// AddGroupMemberHandler adds the user with the username in the request body to the group with the id in the path.
+func AddGroupMemberHandler(s *service.GroupService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userId := auth.UserIdFromContext(r.Context())
+		groupId, ok := pathId(w, r)
+		if !ok {
+			return
+		}
+
+		body := groupMemberBody{}
+		if err := decodeJSON(w, r, &body); err != nil {
+			util.WriteProblem(w, r, fmt.Errorf("%w: body must be a JSON object with a username", util.ErrMalformedBody))
+			return
+		}
+
+		member, err := s.AddMember(r.Context(), util.LoggerFromContext(r.Context()), userId, groupId, body.Username)
+		if err != nil {
+			util.WriteProblem(w, r, err)
+			return
+		}
+		writeJSON(w, http.StatusCreated, member)
+	}
+}
+
+// RemoveGroupMemberHandler removes the user with the userId in the path from the group with the id in the path.
+func RemoveGroupMemberHandler(s *service.GroupService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userId := auth.UserIdFromContext(r.Context())
+		groupId, ok := pathId(w, r)
+		if !ok {
+			return
+		}
+		memberId, ok := pathIdNamed(w, r, "userId")
+		if !ok {
+			return
+		}
+
+		err := s.RemoveMember(r.Context(), util.LoggerFromContext(r.Context()), userId, groupId, memberId)
+		if err != nil {
+			util.WriteProblem(w, r, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// LeaveGroupHandler removes the user from the group with the id in the path.
+func LeaveGroupHandler(s *service.GroupService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userId := auth.UserIdFromContext(r.Context())
+		groupId, ok := pathId(w, r)
+		if !ok {
+			return
+		}
+
+		err := s.LeaveGroup(r.Context(), util.LoggerFromContext(r.Context()), userId, groupId)
+		if err != nil {
+			util.WriteProblem(w, r, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// SetMemberRoleHandler changes the role of the user with the userId in the path in the group with the id in the path to the role in the request body.
+func SetMemberRoleHandler(s *service.GroupService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userId := auth.UserIdFromContext(r.Context())
+		groupId, ok := pathId(w, r)
+		if !ok {
+			return
+		}
+		memberId, ok := pathIdNamed(w, r, "userId")
+		if !ok {
+			return
+		}
+
+		body := roleBody{}
+		if err := decodeJSON(w, r, &body); err != nil {
+			util.WriteProblem(w, r, fmt.Errorf("%w: body must be a JSON object with a role of admin, member or viewer", util.ErrMalformedBody))
+			return
+		}
+
+		member, err := s.SetRole(r.Context(), util.LoggerFromContext(r.Context()), userId, groupId, memberId, body.Role)
+		if err != nil {
+			util.WriteProblem(w, r, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, member)
+	}
+}
+
+// TransferGroupHandler makes the member with the userId in the request body the owner of the group with the id in the path.
+func TransferGroupHandler(s *service.GroupService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userId := auth.UserIdFromContext(r.Context())
+		groupId, ok := pathId(w, r)
+		if !ok {
+			return
+		}
+
+		body := transferBody{}
+		if err := decodeJSON(w, r, &body); err != nil {
+			util.WriteProblem(w, r, fmt.Errorf("%w: body must be a JSON object with a userId", util.ErrMalformedBody))
+			return
+		}
+
+		group, err := s.TransferOwnership(r.Context(), util.LoggerFromContext(r.Context()), userId, groupId, body.UserId)
+		if err != nil {
+			util.WriteProblem(w, r, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, group)
+	}
+}
+
+// GetMemberTimeHandler returns the focused time of the user with the userId in the path in the group with the id in the path.
+// The optional from and to query parameters are RFC 3339 times and default to the last 7 days.
+// Only the focused time within the range counts, running work logs count up to now.
+func GetMemberTimeHandler(s *service.GroupService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userId := auth.UserIdFromContext(r.Context())
+		groupId, ok := pathId(w, r)
+		if !ok {
+			return
+		}
+		memberId, ok := pathIdNamed(w, r, "userId")
+		if !ok {
+			return
+		}
+
+		query := r.URL.Query()
+		from, fromErr := queryTime(query, "from")
+		to, toErr := queryTime(query, "to")
+		if err := errors.Join(fromErr, toErr); err != nil {
+			util.WriteProblem(w, r, err)
+			return
+		}
+
+		memberTime, err := s.GetMemberTime(r.Context(), util.LoggerFromContext(r.Context()), userId, groupId, memberId, from, to)
+		if err != nil {
+			util.WriteProblem(w, r, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, memberTime)
+	}
+}
+
+// GetGroupCategoriesHandler returns the shared categories of the group with the id in the path.
+func GetGroupCategoriesHandler(s *service.GroupService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userId := auth.UserIdFromContext(r.Context())
+		groupId, ok := pathId(w, r)
+		if !ok {
+			return
+		}
+
+		categories, err := s.GetCategories(r.Context(), util.LoggerFromContext(r.Context()), userId, groupId)
+		if err != nil {
+			util.WriteProblem(w, r, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, categories)
+	}
+}
+
+// CreateGroupCategoryHandler creates a shared category with the name in the request body in the group with the id in the path.
+func CreateGroupCategoryHandler(s *service.GroupService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userId := auth.UserIdFromContext(r.Context())
+		groupId, ok := pathId(w, r)
+		if !ok {
+			return
+		}
+
+		body := groupName{}
+		if err := decodeJSON(w, r, &body); err != nil {
+			util.WriteProblem(w, r, fmt.Errorf("%w: body must be a JSON object with a name", util.ErrMalformedBody))
+			return
+		}
+
+		category, err := s.CreateCategory(r.Context(), util.LoggerFromContext(r.Context()), userId, groupId, body.Name)
+		if err != nil {
+			util.WriteProblem(w, r, err)
+			return
+		}
+		writeJSON(w, http.StatusCreated, category)
+	}
+}
+
+// RenameGroupCategoryHandler changes the name of the shared category with the categoryId in the path to the name in the request body.
+func RenameGroupCategoryHandler(s *service.GroupService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userId := auth.UserIdFromContext(r.Context())
+		groupId, ok := pathId(w, r)
+		if !ok {
+			return
+		}
+		categoryId, ok := pathIdNamed(w, r, "categoryId")
+		if !ok {
+			return
+		}
+
+		body := groupName{}
+		if err := decodeJSON(w, r, &body); err != nil {
+			util.WriteProblem(w, r, fmt.Errorf("%w: body must be a JSON object with a name", util.ErrMalformedBody))
+			return
+		}
+
+		category, err := s.RenameCategory(r.Context(), util.LoggerFromContext(r.Context()), userId, groupId, categoryId, body.Name)
+		if err != nil {
+			util.WriteProblem(w, r, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, category)
+	}
+}
+
+// DeleteGroupCategoryHandler deletes the shared category with the categoryId in the path of the group with the id in the path.
+func DeleteGroupCategoryHandler(s *service.GroupService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userId := auth.UserIdFromContext(r.Context())
+		groupId, ok := pathId(w, r)
+		if !ok {
+			return
+		}
+		categoryId, ok := pathIdNamed(w, r, "categoryId")
+		if !ok {
+			return
+		}
+
+		err := s.DeleteCategory(r.Context(), util.LoggerFromContext(r.Context()), userId, groupId, categoryId)
+		if err != nil {
+			util.WriteProblem(w, r, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+type inviteBody struct {
+	MaxUses   uint32 `json:"maxUses"`
+	ExpiresIn int64  `json:"expiresIn"` // In seconds
+}
+
+type joinBody struct {
+	Code string `json:"code"`
+}
+
+// CreateInviteHandler creates an invite to the group with the id in the path.
+// The optional maxUses and expiresIn of the request body limit how often and how long it can be used.
+func CreateInviteHandler(s *service.GroupService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userId := auth.UserIdFromContext(r.Context())
+		groupId, ok := pathId(w, r)
+		if !ok {
+			return
+		}
+
+		body := inviteBody{}
+		if err := decodeJSON(w, r, &body); err != nil {
+			util.WriteProblem(w, r, fmt.Errorf("%w: body must be a JSON object with an optional maxUses and expiresIn", util.ErrMalformedBody))
+			return
+		}
+
+		invite, err := s.CreateInvite(r.Context(), util.LoggerFromContext(r.Context()), userId, groupId, body.MaxUses, time.Duration(body.ExpiresIn)*time.Second)
+		if err != nil {
+			util.WriteProblem(w, r, err)
+			return
+		}
+		writeJSON(w, http.StatusCreated, invite)
+	}
+}
+
+// GetInvitesHandler returns the pending invites of the group with the id in the path.
+func GetInvitesHandler(s *service.GroupService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userId := auth.UserIdFromContext(r.Context())
+		groupId, ok := pathId(w, r)
+		if !ok {
+			return
+		}
+
+		invites, err := s.GetInvites(r.Context(), util.LoggerFromContext(r.Context()), userId, groupId)
+		if err != nil {
+			util.WriteProblem(w, r, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, invites)
+	}
+}
+
+// RevokeInviteHandler revokes the invite with the inviteId in the path of the group with the id in the path.
+func RevokeInviteHandler(s *service.GroupService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userId := auth.UserIdFromContext(r.Context())
+		groupId, ok := pathId(w, r)
+		if !ok {
+			return
+		}
+		inviteId, ok := pathIdNamed(w, r, "inviteId")
+		if !ok {
+			return
+		}
+
+		err := s.RevokeInvite(r.Context(), util.LoggerFromContext(r.Context()), userId, groupId, inviteId)
+		if err != nil {
+			util.WriteProblem(w, r, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// JoinGroupHandler adds the user to the group of the invite code in the request body.
+func JoinGroupHandler(s *service.GroupService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userId := auth.UserIdFromContext(r.Context())
+
+		body := joinBody{}
+		if err := decodeJSON(w, r, &body); err != nil {
+			util.WriteProblem(w, r, fmt.Errorf("%w: body must be a JSON object with a code", util.ErrMalformedBody))
+			return
+		}
+
+		group, err := s.JoinGroup(r.Context(), util.LoggerFromContext(r.Context()), userId, body.Code)
+		if err != nil {
+			util.WriteProblem(w, r, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, group)
+	}
+}
@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+	"sync/atomic"
+
+	"github.com/NerdBow/Grinders-API/internal/database"
+	"github.com/NerdBow/Grinders-API/internal/util"
+)
+
+type readiness struct {
+	Ready        bool   `json:"ready"`
+	Database     string `json:"database"`
+	Schema       string `json:"schema"`
+	ShuttingDown bool   `json:"shuttingDown"`
+}
+
+type buildInfo struct {
+	Module       string `json:"module"`
+	Version      string `json:"version"`
+	Revision     string `json:"revision,omitempty"`
+	RevisionTime string `json:"revisionTime,omitempty"`
+	Modified     bool   `json:"modified"`
+	GoVersion    string `json:"goVersion"`
+}
+
+// HealthzHandler reports that the process is alive. It does not check any dependencies.
+func HealthzHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, struct {
+			Status string `json:"status"`
+		}{"ok"})
+	}
+}
+
+// ReadyzHandler reports if the API can serve requests.
+// It is not ready when the database can't be reached, the schema is missing or the server is shutting down.
+func ReadyzHandler(db database.HealthDB, shuttingDown *atomic.Bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		logger := util.LoggerFromContext(ctx)
+
+		status := readiness{
+			Ready:        true,
+			Database:     "ok",
+			Schema:       "ok",
+			ShuttingDown: shuttingDown.Load(),
+		}
+
+		if err := db.PingContext(ctx); err != nil {
+			logger.LogAttrs(ctx, slog.LevelWarn, "PingContext ReadyzHandler", slog.String("err", err.Error()))
+			status.Ready = false
+			status.Database = "unreachable"
+			status.Schema = "unknown"
+		} else if err := db.CheckSchema(ctx, logger); err != nil {
+			status.Ready = false
+			status.Schema = err.Error()
+		}
+
+		if status.ShuttingDown {
+			status.Ready = false
+		}
+
+		statusCode := http.StatusOK
+		if !status.Ready {
+			statusCode = http.StatusServiceUnavailable
+		}
+		writeJSON(w, statusCode, status)
+	}
+}
+
+// VersionHandler returns the build information of the binary.
+func VersionHandler() http.HandlerFunc {
+	info := buildInfo{Version: "unknown"}
+	if build, ok := debug.ReadBuildInfo(); ok {
+		info.Module = build.Main.Path
+		info.Version = build.Main.Version
+		info.GoVersion = build.GoVersion
+		for _, setting := range build.Settings {
+			switch setting.Key {
+			case "vcs.revision":
+				info.Revision = setting.Value
+			case "vcs.time":
+				info.RevisionTime = setting.Value
+			case "vcs.modified":
+				info.Modified = setting.Value == "true"
+			}
+		}
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, info)
+	}
+}
@@ -0,0 +1,62 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/NerdBow/Grinders-API/internal/auth"
+	"github.com/NerdBow/Grinders-API/internal/service"
+	"github.com/NerdBow/Grinders-API/internal/util"
+)
+
+type idleThresholdBody struct {
+	IdleThreshold int64 `json:"idleThreshold"`
+}
+
+// HeartbeatHandler records that the user is active.
+func HeartbeatHandler(s *service.HeartbeatService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userId := auth.UserIdFromContext(r.Context())
+
+		heartbeat, err := s.Beat(r.Context(), util.LoggerFromContext(r.Context()), userId)
+		if err != nil {
+			util.WriteProblem(w, r, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, heartbeat)
+	}
+}
+
+// GetHeartbeatHandler returns the user's last heartbeat and idle threshold.
+func GetHeartbeatHandler(s *service.HeartbeatService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userId := auth.UserIdFromContext(r.Context())
+
+		heartbeat, err := s.GetHeartbeat(r.Context(), util.LoggerFromContext(r.Context()), userId)
+		if err != nil {
+			util.WriteProblem(w, r, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, heartbeat)
+	}
+}
+
+// SetIdleThresholdHandler sets the user's idle threshold to the idleThreshold seconds in the request body.
+func SetIdleThresholdHandler(s *service.HeartbeatService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userId := auth.UserIdFromContext(r.Context())
+
+		body := idleThresholdBody{}
+		if err := decodeJSON(w, r, &body); err != nil {
+			util.WriteProblem(w, r, fmt.Errorf("%w: body must be a JSON object with an idleThreshold", util.ErrMalformedBody))
+			return
+		}
+
+		heartbeat, err := s.SetIdleThreshold(r.Context(), util.LoggerFromContext(r.Context()), userId, body.IdleThreshold)
+		if err != nil {
+			util.WriteProblem(w, r, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, heartbeat)
+	}
+}
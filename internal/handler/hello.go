@@ -1,13 +0,0 @@
-package handler
-
-import (
-	"net/http"
-)
-
-func HelloHandler() http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusOK)
-		w.Write([]byte("AHHHHH"))
-	}
-}
-
@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/NerdBow/Grinders-API/internal/auth"
+	"github.com/NerdBow/Grinders-API/internal/service"
+	"github.com/NerdBow/Grinders-API/internal/util"
+)
+
+// GetLeaderboardHandler returns the leaderboard of the group with the id in the path for the optional period query parameter of day, week or month.
+// Only the focused time within the period counts, running work logs count up to now.
+func GetLeaderboardHandler(s *service.GroupService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userId := auth.UserIdFromContext(r.Context())
+		groupId, ok := pathId(w, r)
+		if !ok {
+			return
+		}
+
+		leaderboard, err := s.GetLeaderboard(r.Context(), util.LoggerFromContext(r.Context()), userId, groupId, r.URL.Query().Get("period"))
+		if err != nil {
+			util.WriteProblem(w, r, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, leaderboard)
+	}
+}
+
+// GetLeaderboardPrivacyHandler returns if the user opted out of leaderboards.
+func GetLeaderboardPrivacyHandler(s *service.GroupService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userId := auth.UserIdFromContext(r.Context())
+
+		privacy, err := s.GetLeaderboardPrivacy(r.Context(), util.LoggerFromContext(r.Context()), userId)
+		if err != nil {
+			util.WriteProblem(w, r, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, privacy)
+	}
+}
+
+// SetLeaderboardPrivacyHandler opts the user out of leaderboards or back in with the optedOut of the request body.
+func SetLeaderboardPrivacyHandler(s *service.GroupService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userId := auth.UserIdFromContext(r.Context())
+
+		body := util.LeaderboardPrivacy{}
+		if err := decodeJSON(w, r, &body); err != nil {
+			util.WriteProblem(w, r, fmt.Errorf("%w: body must be a JSON object with an optedOut boolean", util.ErrMalformedBody))
+			return
+		}
+
+		privacy, err := s.SetLeaderboardPrivacy(r.Context(), util.LoggerFromContext(r.Context()), userId, body)
+		if err != nil {
+			util.WriteProblem(w, r, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, privacy)
+	}
+}
@@ -0,0 +1,98 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/NerdBow/Grinders-API/internal/auth"
+	"github.com/NerdBow/Grinders-API/internal/service"
+	"github.com/NerdBow/Grinders-API/internal/util"
+)
+
+type startFocusBody struct {
+	TaskId    uint64 `json:"taskId"`
+	Objective string `json:"objective"`
+}
+
+// GetPomodoroHandler returns the current phase of the user's Pomodoro cycle and the seconds remaining in it.
+func GetPomodoroHandler(s *service.PomodoroService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userId := auth.UserIdFromContext(r.Context())
+
+		pomodoro, err := s.GetPomodoro(r.Context(), util.LoggerFromContext(r.Context()), userId)
+		if err != nil {
+			util.WriteProblem(w, r, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, pomodoro)
+	}
+}
+
+// StartFocusHandler starts the next focus phase on the taskId with the objective in the request body.
+// Both may be left out to continue with those of the last focus phase.
+func StartFocusHandler(s *service.PomodoroService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userId := auth.UserIdFromContext(r.Context())
+
+		body := startFocusBody{}
+		if err := decodeJSON(w, r, &body); err != nil {
+			util.WriteProblem(w, r, fmt.Errorf("%w: body must be a JSON object with an optional taskId and objective", util.ErrMalformedBody))
+			return
+		}
+
+		pomodoro, err := s.StartFocus(r.Context(), util.LoggerFromContext(r.Context()), userId, body.TaskId, body.Objective)
+		if err != nil {
+			util.WriteProblem(w, r, err)
+			return
+		}
+		writeJSON(w, http.StatusCreated, pomodoro)
+	}
+}
+
+// StopPomodoroHandler ends the user's Pomodoro cycle.
+func StopPomodoroHandler(s *service.PomodoroService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userId := auth.UserIdFromContext(r.Context())
+
+		err := s.StopPomodoro(r.Context(), util.LoggerFromContext(r.Context()), userId)
+		if err != nil {
+			util.WriteProblem(w, r, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// GetPomodoroSettingsHandler returns the user's Pomodoro settings.
+func GetPomodoroSettingsHandler(s *service.PomodoroService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userId := auth.UserIdFromContext(r.Context())
+
+		settings, err := s.GetSettings(r.Context(), util.LoggerFromContext(r.Context()), userId)
+		if err != nil {
+			util.WriteProblem(w, r, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, settings)
+	}
+}
+
+// SetPomodoroSettingsHandler replaces the user's Pomodoro settings with the ones in the request body.
+func SetPomodoroSettingsHandler(s *service.PomodoroService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userId := auth.UserIdFromContext(r.Context())
+
+		body := util.PomodoroSettings{}
+		if err := decodeJSON(w, r, &body); err != nil {
+			util.WriteProblem(w, r, fmt.Errorf("%w: body must be a JSON object with a focusLength, shortBreakLength, longBreakLength and cyclesBeforeLongBreak", util.ErrMalformedBody))
+			return
+		}
+
+		settings, err := s.SetSettings(r.Context(), util.LoggerFromContext(r.Context()), userId, body)
+		if err != nil {
+			util.WriteProblem(w, r, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, settings)
+	}
+}
@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/NerdBow/Grinders-API/internal/service"
+	"github.com/NerdBow/Grinders-API/internal/util"
+)
+
+// queryUint parses the query parameter name as a positive integer.
+// 0 is returned if the parameter is not given.
+func queryUint(query url.Values, name string, bitSize int) (uint64, error) {
+	value := query.Get(name)
+	if value == "" {
+		return 0, nil
+	}
+	n, err := strconv.ParseUint(value, 10, bitSize)
+	if err != nil || n < 1 {
+		return 0, fmt.Errorf("%w: %s must be a positive integer", util.ErrInvalidQuery, name)
+	}
+	return n, nil
+}
+
+// queryBool parses the query parameter name as a boolean.
+// false is returned if the parameter is not given.
+func queryBool(query url.Values, name string) (bool, error) {
+	value := query.Get(name)
+	if value == "" {
+		return false, nil
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("%w: %s must be true or false", util.ErrInvalidQuery, name)
+	}
+	return b, nil
+}
+
+// queryTime parses the query parameter name as a RFC 3339 time.
+// The zero time is returned if the parameter is not given.
+func queryTime(query url.Values, name string) (time.Time, error) {
+	value := query.Get(name)
+	if value == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: %s must be a RFC 3339 time", util.ErrInvalidQuery, name)
+	}
+	return t, nil
+}
+
+// queryDate parses the query parameter name as a 2006-01-02 date.
+// The zero time is returned if the parameter is not given.
+func queryDate(query url.Values, name string) (time.Time, error) {
+	value := query.Get(name)
+	if value == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(service.DATE_FORMAT, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: %s must be a date formatted as YYYY-MM-DD", util.ErrInvalidQuery, name)
+	}
+	return t, nil
+}
+
+// queryLocation parses the query parameter name as an IANA time zone.
+// UTC is returned if the parameter is not given.
+func queryLocation(query url.Values, name string) (*time.Location, error) {
+	value := query.Get(name)
+	if value == "" {
+		return time.UTC, nil
+	}
+	loc, err := time.LoadLocation(value)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s must be an IANA time zone", util.ErrInvalidQuery, name)
+	}
+	return loc, nil
+}
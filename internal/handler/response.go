@@ -0,0 +1,49 @@
+package handler
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/NerdBow/Grinders-API/internal/util"
+)
+
+const MAX_BODY_SIZE = 1 << 20 // 1 MiB is plenty for any request body of the API
+
+// decodeJSON decodes the request body into v.
+// Unknown fields are rejected so typos in a client are caught early.
+func decodeJSON(w http.ResponseWriter, r *http.Request, v any) error {
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, MAX_BODY_SIZE))
+	decoder.DisallowUnknownFields()
+	return decoder.Decode(v)
+}
+
+// writeJSON writes v as the JSON body of the response with the given status code.
+func writeJSON(w http.ResponseWriter, statusCode int, v any) {
+	responseBytes, err := json.Marshal(v)
+	if err != nil {
+		slog.Error("Marshal writeJSON", slog.String("err", err.Error()))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	w.Write(responseBytes)
+}
+
+// pathId parses the {id} wildcard of the request path.
+// If the id is not a positive integer a problem response is written and ok is false.
+func pathId(w http.ResponseWriter, r *http.Request) (id uint64, ok bool) {
+	return pathIdNamed(w, r, "id")
+}
+
+// pathIdNamed parses the wildcard called name of the request path in the same way as pathId.
+func pathIdNamed(w http.ResponseWriter, r *http.Request, name string) (id uint64, ok bool) {
+	id, err := strconv.ParseUint(r.PathValue(name), 10, 64)
+	if err != nil || id < 1 {
+		util.WriteProblem(w, r, util.ErrInvalidPathId)
+		return 0, false
+	}
+	return id, true
+}
@@ -0,0 +1,44 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/NerdBow/Grinders-API/internal/auth"
+	"github.com/NerdBow/Grinders-API/internal/service"
+	"github.com/NerdBow/Grinders-API/internal/util"
+)
+
+// GetSettingsHandler returns the user's settings.
+func GetSettingsHandler(s *service.UserSettingsService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userId := auth.UserIdFromContext(r.Context())
+
+		settings, err := s.GetSettings(r.Context(), util.LoggerFromContext(r.Context()), userId)
+		if err != nil {
+			util.WriteProblem(w, r, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, settings)
+	}
+}
+
+// SetSettingsHandler replaces the user's settings with the ones in the request body.
+func SetSettingsHandler(s *service.UserSettingsService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userId := auth.UserIdFromContext(r.Context())
+
+		body := util.UserSettings{}
+		if err := decodeJSON(w, r, &body); err != nil {
+			util.WriteProblem(w, r, fmt.Errorf("%w: body must be a JSON object with a timeZone", util.ErrMalformedBody))
+			return
+		}
+
+		settings, err := s.SetSettings(r.Context(), util.LoggerFromContext(r.Context()), userId, body)
+		if err != nil {
+			util.WriteProblem(w, r, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, settings)
+	}
+}
@@ -0,0 +1,74 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/NerdBow/Grinders-API/internal/auth"
+	"github.com/NerdBow/Grinders-API/internal/service"
+	"github.com/NerdBow/Grinders-API/internal/util"
+)
+
+// GetStreaksHandler returns the user's activity streak followed by the streaks of the user's goals.
+func GetStreaksHandler(s *service.StreakService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userId := auth.UserIdFromContext(r.Context())
+
+		streaks, err := s.GetStreaks(r.Context(), util.LoggerFromContext(r.Context()), userId)
+		if err != nil {
+			util.WriteProblem(w, r, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, streaks)
+	}
+}
+
+// GetRestDaysHandler returns the user's rest days between the optional from and to query dates.
+func GetRestDaysHandler(s *service.StreakService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userId := auth.UserIdFromContext(r.Context())
+
+		query := r.URL.Query()
+		_, fromErr := queryDate(query, "from")
+		_, toErr := queryDate(query, "to")
+		if err := errors.Join(fromErr, toErr); err != nil {
+			util.WriteProblem(w, r, err)
+			return
+		}
+
+		restDays, err := s.GetRestDays(r.Context(), util.LoggerFromContext(r.Context()), userId, query.Get("from"), query.Get("to"))
+		if err != nil {
+			util.WriteProblem(w, r, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, restDays)
+	}
+}
+
+// AddRestDayHandler marks the date in the path as a rest day of the user.
+func AddRestDayHandler(s *service.StreakService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userId := auth.UserIdFromContext(r.Context())
+
+		err := s.AddRestDay(r.Context(), util.LoggerFromContext(r.Context()), userId, r.PathValue("date"))
+		if err != nil {
+			util.WriteProblem(w, r, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// DeleteRestDayHandler removes the rest day of the user on the date in the path.
+func DeleteRestDayHandler(s *service.StreakService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userId := auth.UserIdFromContext(r.Context())
+
+		err := s.DeleteRestDay(r.Context(), util.LoggerFromContext(r.Context()), userId, r.PathValue("date"))
+		if err != nil {
+			util.WriteProblem(w, r, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,191 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/NerdBow/Grinders-API/internal/auth"
+	"github.com/NerdBow/Grinders-API/internal/service"
+	"github.com/NerdBow/Grinders-API/internal/util"
+)
+
+type taskBody struct {
+	Name         string    `json:"name"`
+	DeadlineTime time.Time `json:"deadlineTime"`
+	CategoryId   uint64    `json:"categoryId"`
+}
+
+type taskCompletion struct {
+	IsComplete bool `json:"isComplete"`
+}
+
+// GetTasksHandler returns a page of the user's tasks filtered and sorted by the query parameters
+// name, category, sort (deadline, creation or completion), order (asc or desc) and page.
+func GetTasksHandler(s *service.TaskService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userId := auth.UserIdFromContext(r.Context())
+
+		querySettings, ok := taskQuerySettings(w, r)
+		if !ok {
+			return
+		}
+
+		tasks, err := s.QueryTasks(r.Context(), util.LoggerFromContext(r.Context()), userId, querySettings)
+		if err != nil {
+			util.WriteProblem(w, r, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, tasks)
+	}
+}
+
+// GetTaskHandler returns the task with the id in the path.
+func GetTaskHandler(s *service.TaskService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userId := auth.UserIdFromContext(r.Context())
+		taskId, ok := pathId(w, r)
+		if !ok {
+			return
+		}
+
+		task, err := s.GetTask(r.Context(), util.LoggerFromContext(r.Context()), userId, taskId)
+		if err != nil {
+			util.WriteProblem(w, r, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, task)
+	}
+}
+
+// CreateTaskHandler creates a task from the name, deadlineTime and categoryId in the request body.
+func CreateTaskHandler(s *service.TaskService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userId := auth.UserIdFromContext(r.Context())
+
+		body := taskBody{}
+		if err := decodeJSON(w, r, &body); err != nil {
+			util.WriteProblem(w, r, fmt.Errorf("%w: body must be a JSON object with a name, deadlineTime and categoryId", util.ErrMalformedBody))
+			return
+		}
+
+		task, err := s.CreateTask(r.Context(), util.LoggerFromContext(r.Context()), userId, util.Task{Name: body.Name, DeadlineTime: body.DeadlineTime, CategoryId: body.CategoryId})
+		if err != nil {
+			util.WriteProblem(w, r, err)
+			return
+		}
+		writeJSON(w, http.StatusCreated, task)
+	}
+}
+
+// EditTaskHandler changes the fields given in the request body of the task with the id in the path.
+func EditTaskHandler(s *service.TaskService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userId := auth.UserIdFromContext(r.Context())
+		taskId, ok := pathId(w, r)
+		if !ok {
+			return
+		}
+
+		body := taskBody{}
+		if err := decodeJSON(w, r, &body); err != nil {
+			util.WriteProblem(w, r, fmt.Errorf("%w: body must be a JSON object with a name, deadlineTime or categoryId", util.ErrMalformedBody))
+			return
+		}
+
+		task, err := s.EditTask(r.Context(), util.LoggerFromContext(r.Context()), userId, util.Task{Id: taskId, Name: body.Name, DeadlineTime: body.DeadlineTime, CategoryId: body.CategoryId})
+		if err != nil {
+			util.WriteProblem(w, r, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, task)
+	}
+}
+
+// DeleteTaskHandler deletes the task with the id in the path.
+func DeleteTaskHandler(s *service.TaskService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userId := auth.UserIdFromContext(r.Context())
+		taskId, ok := pathId(w, r)
+		if !ok {
+			return
+		}
+
+		err := s.DeleteTask(r.Context(), util.LoggerFromContext(r.Context()), userId, taskId)
+		if err != nil {
+			util.WriteProblem(w, r, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// SetTaskCompletionHandler marks the task with the id in the path as complete or incomplete.
+func SetTaskCompletionHandler(s *service.TaskService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userId := auth.UserIdFromContext(r.Context())
+		taskId, ok := pathId(w, r)
+		if !ok {
+			return
+		}
+
+		body := taskCompletion{}
+		if err := decodeJSON(w, r, &body); err != nil {
+			util.WriteProblem(w, r, fmt.Errorf("%w: body must be a JSON object with isComplete", util.ErrMalformedBody))
+			return
+		}
+
+		task, err := s.SetCompletion(r.Context(), util.LoggerFromContext(r.Context()), userId, taskId, body.IsComplete)
+		if err != nil {
+			util.WriteProblem(w, r, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, task)
+	}
+}
+
+// taskQuerySettings maps the query parameters of the request onto a TaskQuerySettings.
+// If a parameter is invalid a problem response is written and ok is false.
+func taskQuerySettings(w http.ResponseWriter, r *http.Request) (util.TaskQuerySettings, bool) {
+	query := r.URL.Query()
+	querySettings := util.TaskQuerySettings{
+		Name:     query.Get("name"),
+		Category: query.Get("category"),
+	}
+
+	switch query.Get("sort") {
+	case "":
+	case "creation":
+		querySettings.SortType = util.SORT_CREATION
+	case "completion":
+		querySettings.SortType = util.SORT_COMPLETION
+	case "deadline":
+		querySettings.SortType = util.SORT_DEADLINE
+	default:
+		util.WriteProblem(w, r, fmt.Errorf("%w: sort must be one of deadline, creation or completion", util.ErrInvalidQuery))
+		return querySettings, false
+	}
+
+	switch query.Get("order") {
+	case "":
+	case "asc":
+		querySettings.SortOrder = util.ORDER_ASCEDNING
+	case "desc":
+		querySettings.SortOrder = util.ORDER_DESCEDNING
+	default:
+		util.WriteProblem(w, r, fmt.Errorf("%w: order must be one of asc or desc", util.ErrInvalidQuery))
+		return querySettings, false
+	}
+
+	if page := query.Get("page"); page != "" {
+		n, err := strconv.ParseUint(page, 10, 16)
+		if err != nil || n < 1 {
+			util.WriteProblem(w, r, fmt.Errorf("%w: page must be a positive integer", util.ErrInvalidQuery))
+			return querySettings, false
+		}
+		querySettings.Page = uint16(n)
+	}
+
+	return querySettings, true
+}
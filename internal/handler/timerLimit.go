@@ -0,0 +1,89 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/NerdBow/Grinders-API/internal/auth"
+	"github.com/NerdBow/Grinders-API/internal/service"
+	"github.com/NerdBow/Grinders-API/internal/util"
+)
+
+type resolveReviewBody struct {
+	EndTime         time.Time `json:"endTime"`
+	WorkDescription string    `json:"workDescription"`
+}
+
+// GetTimerLimitsHandler returns the user's timer limits.
+func GetTimerLimitsHandler(s *service.TimerLimitService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userId := auth.UserIdFromContext(r.Context())
+
+		limits, err := s.GetLimits(r.Context(), util.LoggerFromContext(r.Context()), userId)
+		if err != nil {
+			util.WriteProblem(w, r, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, limits)
+	}
+}
+
+// SetTimerLimitsHandler replaces the user's timer limits with the ones in the request body.
+func SetTimerLimitsHandler(s *service.TimerLimitService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userId := auth.UserIdFromContext(r.Context())
+
+		body := util.TimerLimits{}
+		if err := decodeJSON(w, r, &body); err != nil {
+			util.WriteProblem(w, r, fmt.Errorf("%w: body must be a JSON object with a maxDuration and cutoffTime", util.ErrMalformedBody))
+			return
+		}
+
+		limits, err := s.SetLimits(r.Context(), util.LoggerFromContext(r.Context()), userId, body)
+		if err != nil {
+			util.WriteProblem(w, r, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, limits)
+	}
+}
+
+// GetReviewsHandler returns the user's work logs that were stopped automatically and need a review.
+func GetReviewsHandler(s *service.TimerLimitService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userId := auth.UserIdFromContext(r.Context())
+
+		reviews, err := s.GetReviews(r.Context(), util.LoggerFromContext(r.Context()), userId)
+		if err != nil {
+			util.WriteProblem(w, r, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, reviews)
+	}
+}
+
+// ResolveReviewHandler marks the work log with the id in the path as reviewed,
+// correcting it with the optional endTime and workDescription in the request body.
+func ResolveReviewHandler(s *service.TimerLimitService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userId := auth.UserIdFromContext(r.Context())
+		workLogId, ok := pathId(w, r)
+		if !ok {
+			return
+		}
+
+		body := resolveReviewBody{}
+		if err := decodeJSON(w, r, &body); err != nil {
+			util.WriteProblem(w, r, fmt.Errorf("%w: body must be a JSON object with an optional RFC 3339 endTime and workDescription", util.ErrMalformedBody))
+			return
+		}
+
+		workLog, err := s.ResolveReview(r.Context(), util.LoggerFromContext(r.Context()), userId, workLogId, body.EndTime, body.WorkDescription)
+		if err != nil {
+			util.WriteProblem(w, r, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, workLog)
+	}
+}
@@ -0,0 +1,250 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/NerdBow/Grinders-API/internal/auth"
+	"github.com/NerdBow/Grinders-API/internal/service"
+	"github.com/NerdBow/Grinders-API/internal/util"
+)
+
+type startWorkLogBody struct {
+	TaskId    uint64 `json:"taskId"`
+	Objective string `json:"objective"`
+}
+
+type addWorkLogBody struct {
+	TaskId          uint64    `json:"taskId"`
+	Objective       string    `json:"objective"`
+	WorkDescription string    `json:"workDescription"`
+	StartTime       time.Time `json:"startTime"`
+	EndTime         time.Time `json:"endTime"`
+}
+
+type splitWorkLogBody struct {
+	SplitTime time.Time `json:"splitTime"`
+	TaskId    uint64    `json:"taskId"`
+	Objective string    `json:"objective"`
+}
+
+type mergeWorkLogsBody struct {
+	WorkLogIds []uint64 `json:"workLogIds"`
+}
+
+type stopWorkLogBody struct {
+	WorkDescription string `json:"workDescription"`
+}
+
+// StartWorkLogHandler starts a timer on the taskId with the objective in the request body.
+// If another timer is running it is stopped when the query parameter switch is true, otherwise the request conflicts.
+func StartWorkLogHandler(s *service.WorkLogService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userId := auth.UserIdFromContext(r.Context())
+
+		switchRunning, err := queryBool(r.URL.Query(), "switch")
+		if err != nil {
+			util.WriteProblem(w, r, err)
+			return
+		}
+
+		body := startWorkLogBody{}
+		if err := decodeJSON(w, r, &body); err != nil {
+			util.WriteProblem(w, r, fmt.Errorf("%w: body must be a JSON object with a taskId and objective", util.ErrMalformedBody))
+			return
+		}
+
+		workLog, err := s.StartWorkLog(r.Context(), util.LoggerFromContext(r.Context()), userId, body.TaskId, body.Objective, switchRunning)
+		if err != nil {
+			util.WriteProblem(w, r, err)
+			return
+		}
+		writeJSON(w, http.StatusCreated, workLog)
+	}
+}
+
+// AddWorkLogHandler records a completed work log with the taskId, objective, workDescription,
+// startTime and endTime in the request body for time that was not tracked with a timer.
+func AddWorkLogHandler(s *service.WorkLogService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userId := auth.UserIdFromContext(r.Context())
+
+		body := addWorkLogBody{}
+		if err := decodeJSON(w, r, &body); err != nil {
+			util.WriteProblem(w, r, fmt.Errorf("%w: body must be a JSON object with a taskId, objective, workDescription and RFC 3339 startTime and endTime", util.ErrMalformedBody))
+			return
+		}
+
+		workLog := util.WorkLog{
+			TaskId:          body.TaskId,
+			Objective:       body.Objective,
+			WorkDescription: body.WorkDescription,
+			StartTime:       body.StartTime,
+			EndTime:         body.EndTime,
+		}
+
+		workLog, err := s.AddWorkLog(r.Context(), util.LoggerFromContext(r.Context()), userId, workLog)
+		if err != nil {
+			util.WriteProblem(w, r, err)
+			return
+		}
+		writeJSON(w, http.StatusCreated, workLog)
+	}
+}
+
+// StopWorkLogHandler stops the timer of the work log with the id in the path.
+func StopWorkLogHandler(s *service.WorkLogService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userId := auth.UserIdFromContext(r.Context())
+		workLogId, ok := pathId(w, r)
+		if !ok {
+			return
+		}
+
+		body := stopWorkLogBody{}
+		if err := decodeJSON(w, r, &body); err != nil {
+			util.WriteProblem(w, r, fmt.Errorf("%w: body must be a JSON object with a workDescription", util.ErrMalformedBody))
+			return
+		}
+
+		workLog, err := s.StopWorkLog(r.Context(), util.LoggerFromContext(r.Context()), userId, workLogId, body.WorkDescription)
+		if err != nil {
+			util.WriteProblem(w, r, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, workLog)
+	}
+}
+
+// GetWorkLogHandler returns the work log with the id in the path together with its pause timeline.
+func GetWorkLogHandler(s *service.WorkLogService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userId := auth.UserIdFromContext(r.Context())
+		workLogId, ok := pathId(w, r)
+		if !ok {
+			return
+		}
+
+		workLog, err := s.GetWorkLog(r.Context(), util.LoggerFromContext(r.Context()), userId, workLogId)
+		if err != nil {
+			util.WriteProblem(w, r, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, workLog)
+	}
+}
+
+// GetWorkLogsHandler returns a page of the user's work logs filtered by the query parameters
+// taskId, from and to (RFC 3339 times the work logs started within) and page.
+func GetWorkLogsHandler(s *service.WorkLogService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userId := auth.UserIdFromContext(r.Context())
+
+		query := r.URL.Query()
+		taskId, taskErr := queryUint(query, "taskId", 64)
+		from, fromErr := queryTime(query, "from")
+		to, toErr := queryTime(query, "to")
+		page, pageErr := queryUint(query, "page", 16)
+		if err := errors.Join(taskErr, fromErr, toErr, pageErr); err != nil {
+			util.WriteProblem(w, r, err)
+			return
+		}
+
+		querySettings := util.WorkLogQuerySettings{
+			TaskId: taskId,
+			From:   from,
+			To:     to,
+			Page:   uint16(page),
+		}
+
+		workLogs, err := s.QueryWorkLogs(r.Context(), util.LoggerFromContext(r.Context()), userId, querySettings)
+		if err != nil {
+			util.WriteProblem(w, r, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, workLogs)
+	}
+}
+
+// SplitWorkLogHandler splits the work log with the id in the path at the splitTime in the request body.
+// The second work log is on the optional taskId with the optional objective.
+func SplitWorkLogHandler(s *service.WorkLogService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userId := auth.UserIdFromContext(r.Context())
+		workLogId, ok := pathId(w, r)
+		if !ok {
+			return
+		}
+
+		body := splitWorkLogBody{}
+		if err := decodeJSON(w, r, &body); err != nil {
+			util.WriteProblem(w, r, fmt.Errorf("%w: body must be a JSON object with a RFC 3339 splitTime and an optional taskId and objective", util.ErrMalformedBody))
+			return
+		}
+
+		workLogs, err := s.SplitWorkLog(r.Context(), util.LoggerFromContext(r.Context()), userId, workLogId, body.SplitTime, body.TaskId, body.Objective)
+		if err != nil {
+			util.WriteProblem(w, r, err)
+			return
+		}
+		writeJSON(w, http.StatusCreated, workLogs)
+	}
+}
+
+// MergeWorkLogsHandler merges the work logs with the workLogIds in the request body into one.
+func MergeWorkLogsHandler(s *service.WorkLogService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userId := auth.UserIdFromContext(r.Context())
+
+		body := mergeWorkLogsBody{}
+		if err := decodeJSON(w, r, &body); err != nil {
+			util.WriteProblem(w, r, fmt.Errorf("%w: body must be a JSON object with a list of workLogIds", util.ErrMalformedBody))
+			return
+		}
+
+		workLog, err := s.MergeWorkLogs(r.Context(), util.LoggerFromContext(r.Context()), userId, body.WorkLogIds)
+		if err != nil {
+			util.WriteProblem(w, r, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, workLog)
+	}
+}
+
+// PauseWorkLogHandler pauses the timer of the work log with the id in the path.
+func PauseWorkLogHandler(s *service.WorkLogService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userId := auth.UserIdFromContext(r.Context())
+		workLogId, ok := pathId(w, r)
+		if !ok {
+			return
+		}
+
+		pause, err := s.PauseWorkLog(r.Context(), util.LoggerFromContext(r.Context()), userId, workLogId)
+		if err != nil {
+			util.WriteProblem(w, r, err)
+			return
+		}
+		writeJSON(w, http.StatusCreated, pause)
+	}
+}
+
+// ResumeWorkLogHandler resumes the paused timer of the work log with the id in the path.
+func ResumeWorkLogHandler(s *service.WorkLogService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userId := auth.UserIdFromContext(r.Context())
+		workLogId, ok := pathId(w, r)
+		if !ok {
+			return
+		}
+
+		pause, err := s.ResumeWorkLog(r.Context(), util.LoggerFromContext(r.Context()), userId, workLogId)
+		if err != nil {
+			util.WriteProblem(w, r, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, pause)
+	}
+}
@@ -0,0 +1,116 @@
+package server
+
+import (
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+)
+
+const (
+	DEFAULT_CORS_METHODS = "GET, POST, PUT, PATCH, DELETE"
+	DEFAULT_CORS_HEADERS = "Authorization, Content-Type, X-Request-ID"
+	CORS_EXPOSED_HEADERS = "X-Request-ID, Retry-After"
+	CORS_MAX_AGE         = "600" // Seconds a browser may cache a preflight response
+
+	CONTENT_SECURITY_POLICY = "default-src 'none'; frame-ancestors 'none'"
+	HSTS_POLICY             = "max-age=63072000; includeSubDomains"
+)
+
+type CORSSettings struct {
+	AllowedOrigins   []string // From env var CORS_ALLOWED_ORIGINS, comma separated. "*" allows all origins, but not with credentials
+	AllowedMethods   string   // From env var CORS_ALLOWED_METHODS, comma separated
+	AllowedHeaders   string   // From env var CORS_ALLOWED_HEADERS, comma separated
+	AllowCredentials bool     // From env var CORS_ALLOW_CREDENTIALS, 0 or 1
+}
+
+// NewCORSSettings creates a new CORSSettings with its field specified by the related env vars.
+// Without CORS_ALLOWED_ORIGINS no cross origin requests are allowed.
+func NewCORSSettings() CORSSettings {
+	settings := CORSSettings{
+		AllowedMethods:   DEFAULT_CORS_METHODS,
+		AllowedHeaders:   DEFAULT_CORS_HEADERS,
+		AllowCredentials: os.Getenv("CORS_ALLOW_CREDENTIALS") == "1",
+	}
+	for _, origin := range strings.Split(os.Getenv("CORS_ALLOWED_ORIGINS"), ",") {
+		origin = strings.TrimSpace(origin)
+		if origin != "" {
+			settings.AllowedOrigins = append(settings.AllowedOrigins, origin)
+		}
+	}
+	if methods := os.Getenv("CORS_ALLOWED_METHODS"); methods != "" {
+		settings.AllowedMethods = methods
+	}
+	if headers := os.Getenv("CORS_ALLOWED_HEADERS"); headers != "" {
+		settings.AllowedHeaders = headers
+	}
+	return settings
+}
+
+func (s CORSSettings) isOriginAllowed(origin string) bool {
+	return slices.Contains(s.AllowedOrigins, "*") || slices.Contains(s.AllowedOrigins, origin)
+}
+
+func (s CORSSettings) isMethodAllowed(method string) bool {
+	for _, allowed := range strings.Split(s.AllowedMethods, ",") {
+		if strings.EqualFold(strings.TrimSpace(allowed), method) {
+			return true
+		}
+	}
+	return false
+}
+
+// cors adds the CORS headers for allowed origins and answers preflight requests.
+// Requests from origins that are not allowed are passed on without CORS headers, so the browser blocks the response.
+func cors(settings CORSSettings, h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Origin")
+
+		origin := r.Header.Get("Origin")
+		if origin == "" || !settings.isOriginAllowed(origin) {
+			h.ServeHTTP(w, r)
+			return
+		}
+
+		// A wildcard is never combined with credentials, the API refuses to start with both.
+		if slices.Contains(settings.AllowedOrigins, "*") {
+			w.Header().Set("Access-Control-Allow-Origin", "*")
+		} else {
+			w.Header().Set("Access-Control-Allow-Origin", origin)
+		}
+		if settings.AllowCredentials {
+			w.Header().Set("Access-Control-Allow-Credentials", "true")
+		}
+
+		requestMethod := r.Header.Get("Access-Control-Request-Method")
+		if r.Method != http.MethodOptions || requestMethod == "" {
+			w.Header().Set("Access-Control-Expose-Headers", CORS_EXPOSED_HEADERS)
+			h.ServeHTTP(w, r)
+			return
+		}
+
+		w.Header().Add("Vary", "Access-Control-Request-Method")
+		w.Header().Add("Vary", "Access-Control-Request-Headers")
+		if settings.isMethodAllowed(requestMethod) {
+			w.Header().Set("Access-Control-Allow-Methods", settings.AllowedMethods)
+			w.Header().Set("Access-Control-Allow-Headers", settings.AllowedHeaders)
+			w.Header().Set("Access-Control-Max-Age", CORS_MAX_AGE)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+// securityHeaders sets the standard security headers on every response.
+// HSTS is only sent when the server is served over TLS.
+func securityHeaders(isTLS bool, h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		w.Header().Set("Referrer-Policy", "no-referrer")
+		w.Header().Set("X-Frame-Options", "DENY")
+		w.Header().Set("Content-Security-Policy", CONTENT_SECURITY_POLICY)
+		if isTLS {
+			w.Header().Set("Strict-Transport-Security", HSTS_POLICY)
+		}
+		h.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,81 @@
+package server
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/NerdBow/Grinders-API/internal/util"
+	"github.com/google/uuid"
+)
+
+const (
+	REQUEST_ID_HEADER     = "X-Request-ID"
+	MAX_REQUEST_ID_LENGTH = 128
+	REQUEST_TIMEOUT       = 10 * time.Second // Deadline for all the database work of a single request
+)
+
+// statusRecorder remembers the status code written by a handler so it can be logged.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (s *statusRecorder) WriteHeader(status int) {
+	s.status = status
+	s.ResponseWriter.WriteHeader(status)
+}
+
+// requestLogger gives every request an id and a logger scoped to it.
+// The id is taken from the X-Request-ID header when the client sends a valid one, otherwise a new one is generated.
+// The id is echoed back in the response so a client can report it.
+func requestLogger(mux *http.ServeMux) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestId := r.Header.Get(REQUEST_ID_HEADER)
+		if !isValidRequestId(requestId) {
+			requestId = uuid.New().String()
+		}
+		w.Header().Set(REQUEST_ID_HEADER, requestId)
+
+		_, pattern := mux.Handler(r)
+		logger := slog.Default().With(
+			slog.String("requestId", requestId),
+			slog.String("method", r.Method),
+			slog.String("route", pattern),
+		)
+
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		start := time.Now()
+		mux.ServeHTTP(recorder, r.WithContext(util.WithLogger(r.Context(), logger)))
+
+		logger.LogAttrs(r.Context(), slog.LevelInfo, "Request completed",
+			slog.Int("status", recorder.status),
+			slog.Duration("duration", time.Since(start)),
+		)
+	})
+}
+
+// requestDeadline puts a deadline of REQUEST_TIMEOUT on the context of every request.
+// The context is also cancelled when the client disconnects, which aborts any running query of the request.
+func requestDeadline(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), REQUEST_TIMEOUT)
+		defer cancel()
+		h.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// isValidRequestId reports if a client supplied request id is safe to put into the logs.
+func isValidRequestId(requestId string) bool {
+	if requestId == "" || len(requestId) > MAX_REQUEST_ID_LENGTH {
+		return false
+	}
+	for _, c := range requestId {
+		isAlphaNumeric := (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+		if !isAlphaNumeric && c != '-' && c != '_' && c != '.' {
+			return false
+		}
+	}
+	return true
+}
@@ -2,46 +2,221 @@ package server
 
 import (
 	"context"
+	"errors"
 	"log/slog"
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
+	"sync/atomic"
 	"syscall"
 	"time"
 
+	"github.com/NerdBow/Grinders-API/internal/auth"
+	"github.com/NerdBow/Grinders-API/internal/database"
+	"github.com/NerdBow/Grinders-API/internal/database/sqlite"
 	"github.com/NerdBow/Grinders-API/internal/handler"
+	"github.com/NerdBow/Grinders-API/internal/service"
 )
 
+// DEFAULT_SHUTDOWN_DRAIN_DELAY is used when SHUTDOWN_DRAIN_DELAY is not set.
+const DEFAULT_SHUTDOWN_DRAIN_DELAY = 5 * time.Second
+
 func Run() {
 	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM)
 	defer stop()
 
+	db, err := sqlite.NewSQLiteDB(os.Getenv("DBFILE"))
+	if err != nil {
+		slog.Error("Unable to open database.", slog.String("error", err.Error()))
+		return
+	}
+	defer db.Close()
+
+	if err := db.CreateTables(); err != nil {
+		slog.Error("Unable to create database tables.", slog.String("error", err.Error()))
+		db.Close()
+		os.Exit(1)
+	}
+
+	authService := service.NewAuthService(&db, &db, &db, auth.NewAuthSettings(), auth.NewTokenSettings(), auth.NewLoginSettings())
+	categoryService := service.NewCategoryService(&db)
+	taskService := service.NewTaskService(&db, &db, &db)
+	workLogService := service.NewWorkLogService(&db, &db, &db, &db)
+	breakService := service.NewBreakService(&db, &db)
+	pomodoroService := service.NewPomodoroService(&db, &db, &db, &db, &db, &db)
+	heartbeatService := service.NewHeartbeatService(&db, &db, &db, &db)
+	settingsService := service.NewUserSettingsService(&db, &db)
+	timerLimitService := service.NewTimerLimitService(&db, &db, &db, &db, &db)
+	goalService := service.NewGoalService(&db, &db, &db, &db, &db)
+	streakService := service.NewStreakService(&db, &db, &db, &db, &db, &db)
+	groupService := service.NewGroupService(&db, &db, &db, &db, &db, &db, &db)
+
+	shuttingDown := atomic.Bool{}
+
 	mux := http.NewServeMux()
-	addHandlers(mux)
+	addHandlers(mux, &db, &shuttingDown, &authService, &categoryService, &taskService, &workLogService, &breakService, &pomodoroService, &heartbeatService, &settingsService, &timerLimitService, &goalService, &streakService, &groupService)
+
+	certFile := os.Getenv("TLS_CERT_FILE")
+	keyFile := os.Getenv("TLS_KEY_FILE")
+	isTLS := certFile != "" && keyFile != ""
 
 	server := http.Server{
 		Addr:              os.Getenv("ADDRESS"),
-		Handler:           mux,
+		Handler:           securityHeaders(isTLS, cors(NewCORSSettings(), requestDeadline(requestLogger(mux)))),
 		ReadHeaderTimeout: time.Second,
 	}
 
-	slog.Info("Starting server.", slog.String("Address", server.Addr))
+	slog.Info("Starting server.", slog.String("Address", server.Addr), slog.Bool("TLS", isTLS))
 
 	go func() {
-		err := server.ListenAndServe()
-		if err != nil {
+		var err error
+		if isTLS {
+			err = server.ListenAndServeTLS(certFile, keyFile)
+		} else {
+			err = server.ListenAndServe()
+		}
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
 			slog.Error("Unable to start server.", slog.String("error", err.Error()))
 			stop()
 		}
 	}()
 
+	go runJob(ctx, "pomodoro", service.POMODORO_TICK_INTERVAL, pomodoroService.AdvanceDuePomodoros)
+	go runJob(ctx, "heartbeat", service.HEARTBEAT_SWEEP_INTERVAL, heartbeatService.PauseIdleWorkLogs)
+	go runJob(ctx, "timer_limit", service.TIMER_LIMIT_INTERVAL, timerLimitService.StopLimitedWorkLogs)
+
 	<-ctx.Done()
+	shuttingDown.Store(true)
+
+	// Keep serving while /readyz fails so load balancers stop sending requests before the listener closes.
+	drainDelay := shutdownDrainDelay()
+	slog.Info("Draining server before shutdown.", slog.Duration("delay", drainDelay))
+	time.Sleep(drainDelay)
 
 	if err := server.Shutdown(context.Background()); err != nil {
 		slog.Error("Unable to gracefully shutdown server.", slog.String("error", err.Error()))
 	}
 }
 
-func addHandlers(mux *http.ServeMux) {
-	mux.HandleFunc("GET /hello", handler.HelloHandler())
+func addHandlers(mux *http.ServeMux, db database.HealthDB, shuttingDown *atomic.Bool, authService *service.AuthService, categoryService *service.CategoryService, taskService *service.TaskService, workLogService *service.WorkLogService, breakService *service.BreakService, pomodoroService *service.PomodoroService, heartbeatService *service.HeartbeatService, settingsService *service.UserSettingsService, timerLimitService *service.TimerLimitService, goalService *service.GoalService, streakService *service.StreakService, groupService *service.GroupService) {
+	mux.HandleFunc("GET /healthz", handler.HealthzHandler())
+	mux.HandleFunc("GET /readyz", handler.ReadyzHandler(db, shuttingDown))
+	mux.HandleFunc("GET /version", handler.VersionHandler())
+
+	mux.HandleFunc("POST /auth/register", handler.RegisterHandler(authService))
+	mux.HandleFunc("POST /auth/login", handler.LoginHandler(authService))
+	mux.HandleFunc("POST /auth/refresh", handler.RefreshHandler(authService))
+	mux.HandleFunc("POST /auth/logout", handler.LogoutHandler(authService))
+
+	mux.HandleFunc("DELETE /admin/lockouts/users/{username}", auth.AdminMiddleware(handler.ClearUserLockoutHandler(authService)))
+	mux.HandleFunc("DELETE /admin/lockouts/ips/{ip}", auth.AdminMiddleware(handler.ClearIpLockoutHandler(authService)))
+
+	mux.HandleFunc("GET /settings", auth.AuthMiddleware(handler.GetSettingsHandler(settingsService)))
+	mux.HandleFunc("PUT /settings", auth.AuthMiddleware(handler.SetSettingsHandler(settingsService)))
+
+	mux.HandleFunc("GET /categories", auth.AuthMiddleware(handler.GetCategoriesHandler(categoryService)))
+	mux.HandleFunc("POST /categories", auth.AuthMiddleware(handler.CreateCategoryHandler(categoryService)))
+	mux.HandleFunc("GET /categories/{name}", auth.AuthMiddleware(handler.GetCategoryHandler(categoryService)))
+	mux.HandleFunc("PATCH /categories/{id}", auth.AuthMiddleware(handler.RenameCategoryHandler(categoryService)))
+	mux.HandleFunc("DELETE /categories/{id}", auth.AuthMiddleware(handler.DeleteCategoryHandler(categoryService)))
+
+	mux.HandleFunc("GET /tasks", auth.AuthMiddleware(handler.GetTasksHandler(taskService)))
+	mux.HandleFunc("POST /tasks", auth.AuthMiddleware(handler.CreateTaskHandler(taskService)))
+	mux.HandleFunc("GET /tasks/{id}", auth.AuthMiddleware(handler.GetTaskHandler(taskService)))
+	mux.HandleFunc("PATCH /tasks/{id}", auth.AuthMiddleware(handler.EditTaskHandler(taskService)))
+	mux.HandleFunc("DELETE /tasks/{id}", auth.AuthMiddleware(handler.DeleteTaskHandler(taskService)))
+	mux.HandleFunc("PUT /tasks/{id}/complete", auth.AuthMiddleware(handler.SetTaskCompletionHandler(taskService)))
+
+	mux.HandleFunc("GET /groups", auth.AuthMiddleware(handler.GetGroupsHandler(groupService)))
+	mux.HandleFunc("POST /groups", auth.AuthMiddleware(handler.CreateGroupHandler(groupService)))
+	mux.HandleFunc("POST /groups/join", auth.AuthMiddleware(handler.JoinGroupHandler(groupService)))
+	mux.HandleFunc("GET /groups/leaderboard/privacy", auth.AuthMiddleware(handler.GetLeaderboardPrivacyHandler(groupService)))
+	mux.HandleFunc("PUT /groups/leaderboard/privacy", auth.AuthMiddleware(handler.SetLeaderboardPrivacyHandler(groupService)))
+	mux.HandleFunc("GET /groups/{id}", auth.AuthMiddleware(handler.GetGroupHandler(groupService)))
+	mux.HandleFunc("PATCH /groups/{id}", auth.AuthMiddleware(handler.RenameGroupHandler(groupService)))
+	mux.HandleFunc("DELETE /groups/{id}", auth.AuthMiddleware(handler.DeleteGroupHandler(groupService)))
+	mux.HandleFunc("POST /groups/{id}/leave", auth.AuthMiddleware(handler.LeaveGroupHandler(groupService)))
+	mux.HandleFunc("GET /groups/{id}/members", auth.AuthMiddleware(handler.GetGroupMembersHandler(groupService)))
+	mux.HandleFunc("POST /groups/{id}/members", auth.AuthMiddleware(handler.AddGroupMemberHandler(groupService)))
+	mux.HandleFunc("DELETE /groups/{id}/members/{userId}", auth.AuthMiddleware(handler.RemoveGroupMemberHandler(groupService)))
+	mux.HandleFunc("PUT /groups/{id}/members/{userId}/role", auth.AuthMiddleware(handler.SetMemberRoleHandler(groupService)))
+	mux.HandleFunc("GET /groups/{id}/members/{userId}/time", auth.AuthMiddleware(handler.GetMemberTimeHandler(groupService)))
+	mux.HandleFunc("GET /groups/{id}/leaderboard", auth.AuthMiddleware(handler.GetLeaderboardHandler(groupService)))
+	mux.HandleFunc("POST /groups/{id}/transfer", auth.AuthMiddleware(handler.TransferGroupHandler(groupService)))
+	mux.HandleFunc("GET /groups/{id}/categories", auth.AuthMiddleware(handler.GetGroupCategoriesHandler(groupService)))
+	mux.HandleFunc("POST /groups/{id}/categories", auth.AuthMiddleware(handler.CreateGroupCategoryHandler(groupService)))
+	mux.HandleFunc("PATCH /groups/{id}/categories/{categoryId}", auth.AuthMiddleware(handler.RenameGroupCategoryHandler(groupService)))
+	mux.HandleFunc("DELETE /groups/{id}/categories/{categoryId}", auth.AuthMiddleware(handler.DeleteGroupCategoryHandler(groupService)))
+	mux.HandleFunc("GET /groups/{id}/invites", auth.AuthMiddleware(handler.GetInvitesHandler(groupService)))
+	mux.HandleFunc("POST /groups/{id}/invites", auth.AuthMiddleware(handler.CreateInviteHandler(groupService)))
+	mux.HandleFunc("DELETE /groups/{id}/invites/{inviteId}", auth.AuthMiddleware(handler.RevokeInviteHandler(groupService)))
+
+	mux.HandleFunc("GET /goals", auth.AuthMiddleware(handler.GetGoalsHandler(goalService)))
+	mux.HandleFunc("POST /goals", auth.AuthMiddleware(handler.CreateGoalHandler(goalService)))
+	mux.HandleFunc("GET /goals/progress", auth.AuthMiddleware(handler.GetAllGoalProgressHandler(goalService)))
+	mux.HandleFunc("GET /goals/{id}", auth.AuthMiddleware(handler.GetGoalHandler(goalService)))
+	mux.HandleFunc("PATCH /goals/{id}", auth.AuthMiddleware(handler.EditGoalHandler(goalService)))
+	mux.HandleFunc("DELETE /goals/{id}", auth.AuthMiddleware(handler.DeleteGoalHandler(goalService)))
+	mux.HandleFunc("GET /goals/{id}/progress", auth.AuthMiddleware(handler.GetGoalProgressHandler(goalService)))
+
+	mux.HandleFunc("GET /streaks", auth.AuthMiddleware(handler.GetStreaksHandler(streakService)))
+	mux.HandleFunc("GET /streaks/restdays", auth.AuthMiddleware(handler.GetRestDaysHandler(streakService)))
+	mux.HandleFunc("PUT /streaks/restdays/{date}", auth.AuthMiddleware(handler.AddRestDayHandler(streakService)))
+	mux.HandleFunc("DELETE /streaks/restdays/{date}", auth.AuthMiddleware(handler.DeleteRestDayHandler(streakService)))
+
+	mux.HandleFunc("GET /worklogs", auth.AuthMiddleware(handler.GetWorkLogsHandler(workLogService)))
+	mux.HandleFunc("POST /worklogs", auth.AuthMiddleware(handler.StartWorkLogHandler(workLogService)))
+	mux.HandleFunc("POST /worklogs/manual", auth.AuthMiddleware(handler.AddWorkLogHandler(workLogService)))
+	mux.HandleFunc("POST /worklogs/merge", auth.AuthMiddleware(handler.MergeWorkLogsHandler(workLogService)))
+	mux.HandleFunc("GET /worklogs/limits", auth.AuthMiddleware(handler.GetTimerLimitsHandler(timerLimitService)))
+	mux.HandleFunc("PUT /worklogs/limits", auth.AuthMiddleware(handler.SetTimerLimitsHandler(timerLimitService)))
+	mux.HandleFunc("GET /worklogs/reviews", auth.AuthMiddleware(handler.GetReviewsHandler(timerLimitService)))
+	mux.HandleFunc("PUT /worklogs/{id}/review", auth.AuthMiddleware(handler.ResolveReviewHandler(timerLimitService)))
+	mux.HandleFunc("GET /worklogs/{id}", auth.AuthMiddleware(handler.GetWorkLogHandler(workLogService)))
+	mux.HandleFunc("PUT /worklogs/{id}/stop", auth.AuthMiddleware(handler.StopWorkLogHandler(workLogService)))
+	mux.HandleFunc("PUT /worklogs/{id}/pause", auth.AuthMiddleware(handler.PauseWorkLogHandler(workLogService)))
+	mux.HandleFunc("PUT /worklogs/{id}/resume", auth.AuthMiddleware(handler.ResumeWorkLogHandler(workLogService)))
+	mux.HandleFunc("POST /worklogs/{id}/split", auth.AuthMiddleware(handler.SplitWorkLogHandler(workLogService)))
+
+	mux.HandleFunc("POST /worklogs/{id}/breaks", auth.AuthMiddleware(handler.StartBreakHandler(breakService)))
+	mux.HandleFunc("PUT /breaks/{id}/end", auth.AuthMiddleware(handler.EndBreakHandler(breakService)))
+	mux.HandleFunc("GET /breaks/daily", auth.AuthMiddleware(handler.GetDailyBreaksHandler(breakService)))
+
+	mux.HandleFunc("GET /pomodoro", auth.AuthMiddleware(handler.GetPomodoroHandler(pomodoroService)))
+	mux.HandleFunc("POST /pomodoro", auth.AuthMiddleware(handler.StartFocusHandler(pomodoroService)))
+	mux.HandleFunc("DELETE /pomodoro", auth.AuthMiddleware(handler.StopPomodoroHandler(pomodoroService)))
+	mux.HandleFunc("GET /pomodoro/settings", auth.AuthMiddleware(handler.GetPomodoroSettingsHandler(pomodoroService)))
+	mux.HandleFunc("PUT /pomodoro/settings", auth.AuthMiddleware(handler.SetPomodoroSettingsHandler(pomodoroService)))
+
+	mux.HandleFunc("POST /heartbeat", auth.AuthMiddleware(handler.HeartbeatHandler(heartbeatService)))
+	mux.HandleFunc("GET /heartbeat", auth.AuthMiddleware(handler.GetHeartbeatHandler(heartbeatService)))
+	mux.HandleFunc("PUT /heartbeat/threshold", auth.AuthMiddleware(handler.SetIdleThresholdHandler(heartbeatService)))
+}
+
+// shutdownDrainDelay returns the delay between failing /readyz and shutting down.
+// From env var SHUTDOWN_DRAIN_DELAY in seconds, 0 shuts down right away.
+func shutdownDrainDelay() time.Duration {
+	seconds, err := strconv.ParseUint(os.Getenv("SHUTDOWN_DRAIN_DELAY"), 10, 32)
+	if err != nil {
+		return DEFAULT_SHUTDOWN_DRAIN_DELAY
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// runJob runs job every interval until ctx is done.
+// Background jobs keep timers consistent even when no client is asking, like ending pomodoro phases on time.
+func runJob(ctx context.Context, name string, interval time.Duration, job func(ctx context.Context, logger *slog.Logger)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	logger := slog.Default().With(slog.String("job", name))
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			job(ctx, logger)
+		}
+	}
 }
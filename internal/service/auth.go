@@ -1,8 +1,11 @@
 package service
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"strings"
 	"time"
 
 	"github.com/NerdBow/Grinders-API/internal/auth"
@@ -12,29 +15,46 @@ import (
 
 const MIN_PASSWORD_LENGHT = 8
 
+const (
+	USER_ATTEMPT_PREFIX = "user:" // Key prefix of the failed logins of a username
+	IP_ATTEMPT_PREFIX   = "ip:"   // Key prefix of the failed logins of a client ip
+)
+
+// passwordHasher hashes and checks passwords, it is implemented by auth.ArgonSettings.
+type passwordHasher interface {
+	CreateNewHash(password string) string
+	CompareHash(password, hash string) bool
+}
+
 type AuthService struct {
 	userDb        database.UsersDB
 	sessionDb     database.SessionsDB
-	authSettings  auth.ArgonSettings
+	attemptDb     database.LoginAttemptsDB
+	authSettings  passwordHasher
 	tokenSettings auth.TokenSettings
+	loginSettings auth.LoginSettings
+	dummyHash     string // Compared against when the username does not exist
 }
 
-func NewAuthService(userDb database.UsersDB, sessionDb database.SessionsDB, authSettings auth.ArgonSettings, tokenSettings auth.TokenSettings) AuthService {
+func NewAuthService(userDb database.UsersDB, sessionDb database.SessionsDB, attemptDb database.LoginAttemptsDB, authSettings auth.ArgonSettings, tokenSettings auth.TokenSettings, loginSettings auth.LoginSettings) AuthService {
 	return AuthService{
 		userDb:        userDb,
 		sessionDb:     sessionDb,
+		attemptDb:     attemptDb,
 		authSettings:  authSettings,
 		tokenSettings: tokenSettings,
+		loginSettings: loginSettings,
+		dummyHash:     authSettings.CreateNewHash("dummy password"),
 	}
 }
 
-func (s *AuthService) RegisterNewUser(logger *slog.Logger, username string, password string) error {
+func (s *AuthService) RegisterNewUser(ctx context.Context, logger *slog.Logger, username string, password string) error {
 	if username == "" {
-		slog.Info("")
+		logger.Info("RegisterNewUser empty username")
 		return fmt.Errorf("%w for a username", util.ErrEmptyString)
 	}
 	if len(password) < MIN_PASSWORD_LENGHT {
-		slog.Info("")
+		logger.Info("RegisterNewUser password too short")
 		return util.ErrBadPassword
 	}
 
@@ -43,7 +63,7 @@ func (s *AuthService) RegisterNewUser(logger *slog.Logger, username string, pass
 		Hash:         s.authSettings.CreateNewHash(password),
 		CreationTime: time.Now().UTC(),
 	}
-	err := s.userDb.AddUser(logger, user)
+	err := s.userDb.AddUser(ctx, logger, user)
 	if err != nil {
 		return err
 	}
@@ -51,18 +71,38 @@ func (s *AuthService) RegisterNewUser(logger *slog.Logger, username string, pass
 	return nil
 }
 
-func (s *AuthService) Login(logger *slog.Logger, username string, password string) (util.Tokens, error) {
-	user, err := s.userDb.GetUserByUsername(logger, username)
+// Login checks the password of the user and creates a new session.
+// Failed logins are counted per username and per clientIp, each failure makes the next login wait longer
+// and the username is locked after MaxFailures consecutive failures. This is checked before the password is hashed.
+func (s *AuthService) Login(ctx context.Context, logger *slog.Logger, username string, password string, clientIp string) (util.Tokens, error) {
+	// The failure is counted before the password is hashed so logins sent in parallel wait for each other as well.
+	// It is undone again when the login succeeds.
+	attempts, err := s.recordLoginFailure(ctx, logger, time.Now().UTC(), IP_ATTEMPT_PREFIX+clientIp, USER_ATTEMPT_PREFIX+username)
+	if err != nil {
+		return util.Tokens{}, err
+	}
+
+	user, err := s.userDb.GetUserByUsername(ctx, logger, username)
+	if errors.Is(err, util.ErrUserNotFound) {
+		// The password is still hashed so the time the login takes does not tell if the username exists.
+		s.authSettings.CompareHash(password, s.dummyHash)
+		return util.Tokens{}, err
+	}
 	if err != nil {
 		return util.Tokens{}, err
 	}
 
 	isValid := s.authSettings.CompareHash(password, user.Hash)
 	if !isValid {
-		slog.Info("")
+		logger.Info("Login password mismatch", slog.Uint64("userId", user.Id))
 		return util.Tokens{}, util.ErrHashMismatch
 	}
 
+	err = s.clearLoginFailure(ctx, logger, attempts)
+	if err != nil {
+		return util.Tokens{}, err
+	}
+
 	access, _, err := s.tokenSettings.CreateAccessToken(user.Id)
 	if err != nil {
 		return util.Tokens{}, err
@@ -80,7 +120,7 @@ func (s *AuthService) Login(logger *slog.Logger, username string, password strin
 		UserId:         refresh.UserId,
 	}
 
-	err = s.sessionDb.AddSession(logger, session)
+	err = s.sessionDb.AddSession(ctx, logger, session)
 	if err != nil {
 		return util.Tokens{}, err
 	}
@@ -93,12 +133,12 @@ func (s *AuthService) Login(logger *slog.Logger, username string, password strin
 	return tokens, nil
 }
 
-func (s *AuthService) Refresh(logger *slog.Logger, refreshToken string, userId uint64) (util.Tokens, error) {
+func (s *AuthService) Refresh(ctx context.Context, logger *slog.Logger, refreshToken string, userId uint64) (util.Tokens, error) {
 	if userId < 1 {
 		return util.Tokens{}, util.ErrInvalidUserId
 	}
 
-	session, err := s.sessionDb.GetSession(logger, auth.HashRefreshTokenId(refreshToken), userId)
+	session, err := s.sessionDb.GetSession(ctx, logger, auth.HashRefreshTokenId(refreshToken), userId)
 
 	if err != nil {
 		return util.Tokens{}, err
@@ -117,7 +157,7 @@ func (s *AuthService) Refresh(logger *slog.Logger, refreshToken string, userId u
 		return util.Tokens{}, err
 	}
 
-	err = s.sessionDb.DeleteSession(logger, session.HashedId)
+	err = s.sessionDb.DeleteSession(ctx, logger, session.HashedId)
 	if err != nil {
 		return util.Tokens{}, err
 	}
@@ -128,16 +168,114 @@ func (s *AuthService) Refresh(logger *slog.Logger, refreshToken string, userId u
 		CreationTime:   refresh.CreationTime,
 		UserId:         refresh.UserId,
 	}
-	err = s.sessionDb.AddSession(logger, session)
+	err = s.sessionDb.AddSession(ctx, logger, session)
 
 	if err != nil {
 		return util.Tokens{}, err
 	}
 
 	tokens := util.Tokens{
-		Access: access,
+		Access:  access,
 		Refresh: refresh.Id,
 	}
 
 	return tokens, nil
 }
+
+func (s *AuthService) Logout(ctx context.Context, logger *slog.Logger, refreshToken string, userId uint64) error {
+	if userId < 1 {
+		return util.ErrInvalidUserId
+	}
+
+	session, err := s.sessionDb.GetSession(ctx, logger, auth.HashRefreshTokenId(refreshToken), userId)
+	if err != nil {
+		return err
+	}
+	if session.HashedId == "" {
+		return util.ErrSessionExpired
+	}
+
+	err = s.sessionDb.DeleteSession(ctx, logger, session.HashedId)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ClearLoginAttempts removes the lockout and failed logins of a username or client ip, specified by the key prefix.
+func (s *AuthService) ClearLoginAttempts(ctx context.Context, logger *slog.Logger, prefix string, value string) error {
+	if value == "" {
+		return fmt.Errorf("%w for a username or ip", util.ErrEmptyString)
+	}
+
+	logger.Info("ClearLoginAttempts", slog.String("key", prefix+value))
+	return s.attemptDb.DeleteLoginAttempt(ctx, logger, prefix+value)
+}
+
+// recordLoginFailure counts a failed login at now for all keys and returns the counted attempts.
+// If any key is locked a RetryError of ErrAccountLocked or ErrLoginThrottled is returned instead.
+// Keys before the locked key keep the failure, which is why Login counts the client ip before the username.
+func (s *AuthService) recordLoginFailure(ctx context.Context, logger *slog.Logger, now time.Time, keys ...string) ([]util.LoginAttempt, error) {
+	// Failures stop being consecutive once a lockout has passed since the last one.
+	resetBefore := now.Add(-s.loginSettings.LockoutDuration)
+
+	attempts := make([]util.LoginAttempt, 0, len(keys))
+	for _, key := range keys {
+		isUser := strings.HasPrefix(key, USER_ATTEMPT_PREFIX)
+		attempt, err := s.attemptDb.AddLoginFailure(ctx, logger, key, now, resetBefore, s.lockTimes(now, isUser))
+		if errors.Is(err, util.ErrLoginThrottled) {
+			if isUser && attempt.Failures >= s.loginSettings.MaxFailures {
+				logger.Info("Login locked", slog.String("key", key))
+				return nil, &util.RetryError{Err: util.ErrAccountLocked, After: attempt.LockedUntil.Sub(now)}
+			}
+			logger.Info("Login throttled", slog.String("key", key))
+			return nil, &util.RetryError{Err: util.ErrLoginThrottled, After: attempt.LockedUntil.Sub(now)}
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if isUser && attempt.Failures == s.loginSettings.MaxFailures {
+			logger.Warn("Login lockout", slog.String("key", key), slog.Uint64("failures", uint64(attempt.Failures)))
+		}
+		attempts = append(attempts, attempt)
+	}
+	return attempts, nil
+}
+
+// lockTimes returns until when a key is locked after each amount of consecutive failures counted at now, starting at one failure.
+// Every failure doubles the back-off until it reaches the lockout, usernames are locked for the whole lockout once they reach MaxFailures.
+// The last time is the lock for any amount of failures after it.
+func (s *AuthService) lockTimes(now time.Time, isUser bool) []time.Time {
+	times := make([]time.Time, 0)
+	for failures := uint32(1); ; failures++ {
+		backoff := s.loginSettings.Backoff(failures)
+		if isUser && failures >= s.loginSettings.MaxFailures {
+			backoff = s.loginSettings.LockoutDuration
+		}
+
+		times = append(times, now.Add(backoff))
+		if backoff == s.loginSettings.LockoutDuration {
+			return times
+		}
+	}
+}
+
+// clearLoginFailure undoes the failures counted for a login that succeeded.
+// The failures of the username are cleared, but the client ip only loses the failure of this login,
+// so logging into one account does not reset the throttling of guesses against other usernames.
+func (s *AuthService) clearLoginFailure(ctx context.Context, logger *slog.Logger, attempts []util.LoginAttempt) error {
+	for _, attempt := range attempts {
+		var err error
+		if strings.HasPrefix(attempt.Key, USER_ATTEMPT_PREFIX) {
+			err = s.attemptDb.DeleteLoginAttempt(ctx, logger, attempt.Key)
+		} else {
+			err = s.attemptDb.RemoveLoginFailure(ctx, logger, attempt)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/NerdBow/Grinders-API/internal/auth"
+	"github.com/NerdBow/Grinders-API/internal/database/sqlite"
+	"github.com/NerdBow/Grinders-API/internal/util"
+)
+
+// countingHasher counts the passwords it compares and never matches them.
+type countingHasher struct {
+	compared atomic.Int32
+}
+
+func (h *countingHasher) CreateNewHash(password string) string {
+	return "hash"
+}
+
+func (h *countingHasher) CompareHash(password, hash string) bool {
+	h.compared.Add(1)
+	// Hashing takes long enough for the other logins to arrive while it runs.
+	time.Sleep(50 * time.Millisecond)
+	return false
+}
+
+// newAuthTestService returns an AuthService with a countingHasher on an empty database in a temporary directory.
+// The database has the user named user.
+func newAuthTestService(t *testing.T, loginSettings auth.LoginSettings) (AuthService, *countingHasher, *sqlite.SQLiteDB) {
+	t.Helper()
+
+	db, err := sqlite.NewSQLiteDB(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("NewSQLiteDB returned %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	if err := db.CreateTables(); err != nil {
+		t.Fatalf("CreateTables returned %v", err)
+	}
+	if err := db.AddUser(context.Background(), testLogger(), util.User{Username: "user", Hash: "hash", CreationTime: march(1)}); err != nil {
+		t.Fatalf("AddUser returned %v", err)
+	}
+
+	hasher := &countingHasher{}
+	s := AuthService{
+		userDb:        &db,
+		sessionDb:     &db,
+		attemptDb:     &db,
+		authSettings:  hasher,
+		loginSettings: loginSettings,
+		dummyHash:     "dummy",
+	}
+	return s, hasher, &db
+}
+
+func TestLoginConcurrentFailures(t *testing.T) {
+	ctx := context.Background()
+	s, hasher, _ := newAuthTestService(t, auth.LoginSettings{MaxFailures: 5, LockoutDuration: time.Minute})
+
+	const logins = 10
+	errs := make([]error, logins)
+	wg := sync.WaitGroup{}
+	for i := range logins {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// Every login comes from another client ip, so only the username throttles them.
+			_, errs[i] = s.Login(ctx, testLogger(), "user", "password", fmt.Sprintf("10.0.0.%d", i))
+		}()
+	}
+	wg.Wait()
+
+	if n := hasher.compared.Load(); n != 1 {
+		t.Errorf("CompareHash called %d times, want 1", n)
+	}
+
+	mismatched := 0
+	for _, err := range errs {
+		retryErr := &util.RetryError{}
+		switch {
+		case errors.Is(err, util.ErrHashMismatch):
+			mismatched++
+		case errors.As(err, &retryErr) && errors.Is(err, util.ErrLoginThrottled):
+			if retryErr.After <= 0 {
+				t.Errorf("throttled login retries after %v, want a positive wait", retryErr.After)
+			}
+		default:
+			t.Errorf("Login returned %v, want ErrHashMismatch or ErrLoginThrottled", err)
+		}
+	}
+	if mismatched != 1 {
+		t.Errorf("%d logins returned ErrHashMismatch, want 1", mismatched)
+	}
+}
+
+func TestLoginUnknownUser(t *testing.T) {
+	ctx := context.Background()
+	// Failures expire right away, so the attempts of the first login are expired by the second.
+	s, hasher, db := newAuthTestService(t, auth.LoginSettings{MaxFailures: 5, LockoutDuration: time.Millisecond})
+
+	_, err := s.Login(ctx, testLogger(), "first", "password", "10.0.0.1")
+	if !errors.Is(err, util.ErrUserNotFound) {
+		t.Fatalf("Login returned %v, want ErrUserNotFound", err)
+	}
+	if n := hasher.compared.Load(); n != 1 {
+		t.Errorf("CompareHash called %d times, want 1 so the login takes as long as for existing users", n)
+	}
+
+	time.Sleep(10 * time.Millisecond)
+	_, err = s.Login(ctx, testLogger(), "second", "password", "10.0.0.2")
+	if !errors.Is(err, util.ErrUserNotFound) {
+		t.Fatalf("Login returned %v, want ErrUserNotFound", err)
+	}
+
+	keys := make([]string, 0)
+	rows, err := db.Query("SELECT key FROM login_attempts ORDER BY key;")
+	if err != nil {
+		t.Fatalf("Query returned %v", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		key := ""
+		if err := rows.Scan(&key); err != nil {
+			t.Fatalf("Scan returned %v", err)
+		}
+		keys = append(keys, key)
+	}
+	if want := []string{"ip:10.0.0.2", "user:second"}; !slices.Equal(keys, want) {
+		t.Errorf("login attempts of %v, want %v", keys, want)
+	}
+}
@@ -0,0 +1,156 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/NerdBow/Grinders-API/internal/database"
+	"github.com/NerdBow/Grinders-API/internal/util"
+)
+
+const (
+	DATE_FORMAT     = "2006-01-02"
+	MAX_REPORT_DAYS = 366
+)
+
+// BreakService handles breaks, planned rest taken after a work log is stopped.
+// They are kept apart from pauses, which interrupt a running work log.
+type BreakService struct {
+	breakDb   database.BreaksDB
+	workLogDb database.WorkLogsDB
+}
+
+func NewBreakService(breakDb database.BreaksDB, workLogDb database.WorkLogsDB) BreakService {
+	return BreakService{
+		breakDb:   breakDb,
+		workLogDb: workLogDb,
+	}
+}
+
+// StartBreak starts a break after the stopped work log.
+// A user can only have one running break.
+func (s *BreakService) StartBreak(ctx context.Context, logger *slog.Logger, userId uint64, workLogId uint64) (util.Break, error) {
+	if userId < 1 {
+		return util.Break{}, util.ErrInvalidUserId
+	}
+	if workLogId < 1 {
+		return util.Break{}, util.ErrInvalidWorkLogId
+	}
+
+	workLog, err := s.workLogDb.GetWorkLog(ctx, logger, workLogId, userId)
+	if err != nil {
+		return util.Break{}, err
+	}
+	if !workLog.IsComplete {
+		return util.Break{}, util.ErrWorkLogRunning
+	}
+
+	brk := util.Break{
+		StartTime: time.Now().UTC(),
+		WorkLogId: workLog.Id,
+	}
+
+	brk.Id, err = s.breakDb.AddBreak(ctx, logger, brk)
+	if err != nil {
+		return util.Break{}, err
+	}
+
+	return brk, nil
+}
+
+// EndBreak ends the running break.
+func (s *BreakService) EndBreak(ctx context.Context, logger *slog.Logger, userId uint64, breakId uint64) (util.Break, error) {
+	if userId < 1 {
+		return util.Break{}, util.ErrInvalidUserId
+	}
+	if breakId < 1 {
+		return util.Break{}, util.ErrInvalidBreakId
+	}
+
+	brk, err := s.breakDb.GetBreak(ctx, logger, breakId, userId)
+	if err != nil {
+		return util.Break{}, err
+	}
+	if !brk.EndTime.IsZero() {
+		return util.Break{}, util.ErrBreakEnded
+	}
+
+	brk.EndTime = time.Now().UTC()
+	brk.Duration = int64(brk.EndTime.Sub(brk.StartTime) / time.Second)
+
+	err = s.breakDb.EndBreak(ctx, logger, brk)
+	if err != nil {
+		return util.Break{}, err
+	}
+
+	return brk, nil
+}
+
+// GetDailyBreakTotals returns the total break time of every day from the first to the last day, in the location loc.
+// A break spanning midnight is split between the days. A running break is counted up to now.
+func (s *BreakService) GetDailyBreakTotals(ctx context.Context, logger *slog.Logger, userId uint64, firstDay time.Time, lastDay time.Time, loc *time.Location) ([]util.DailyTotal, error) {
+	if userId < 1 {
+		return nil, util.ErrInvalidUserId
+	}
+
+	days := dayStarts(firstDay, lastDay, loc)
+	if len(days) == 0 || len(days) > MAX_REPORT_DAYS {
+		return nil, util.ErrInvalidTimeRange
+	}
+	end := days[len(days)-1].AddDate(0, 0, 1)
+
+	breaks, err := s.breakDb.QueryBreaks(ctx, logger, userId, days[0].UTC(), end.UTC())
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	totals := make([]util.DailyTotal, 0, len(days))
+	for _, day := range days {
+		nextDay := day.AddDate(0, 0, 1)
+
+		var total time.Duration
+		for _, brk := range breaks {
+			breakEnd := brk.EndTime
+			if breakEnd.IsZero() {
+				breakEnd = now
+			}
+			total += overlap(brk.StartTime, breakEnd, day, nextDay)
+		}
+
+		totals = append(totals, util.DailyTotal{
+			Date:     day.Format(DATE_FORMAT),
+			Duration: int64(total / time.Second),
+		})
+	}
+
+	return totals, nil
+}
+
+// dayStarts returns the midnight of every day from the first to the last day in loc.
+func dayStarts(firstDay time.Time, lastDay time.Time, loc *time.Location) []time.Time {
+	day := time.Date(firstDay.Year(), firstDay.Month(), firstDay.Day(), 0, 0, 0, 0, loc)
+	last := time.Date(lastDay.Year(), lastDay.Month(), lastDay.Day(), 0, 0, 0, 0, loc)
+
+	days := make([]time.Time, 0, 31)
+	for !day.After(last) && len(days) <= MAX_REPORT_DAYS {
+		days = append(days, day)
+		day = day.AddDate(0, 0, 1)
+	}
+	return days
+}
+
+// overlap returns how much of the interval start to end lies within from to.
+func overlap(start time.Time, end time.Time, from time.Time, to time.Time) time.Duration {
+	if start.Before(from) {
+		start = from
+	}
+	if end.After(to) {
+		end = to
+	}
+	if !end.After(start) {
+		return 0
+	}
+	return end.Sub(start)
+}
@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"fmt"
 	"log/slog"
 
@@ -18,31 +19,31 @@ func NewCategoryService(categoryDb database.CategoriesDB) CategoryService {
 	}
 }
 
-func (s *CategoryService) CreateCategory(logger *slog.Logger, userId uint64, name string) error {
+func (s *CategoryService) CreateCategory(ctx context.Context, logger *slog.Logger, userId uint64, name string) (util.Category, error) {
 	if userId < 1 {
-		return util.ErrInvalidUserId
+		return util.Category{}, util.ErrInvalidUserId
 	}
 	if name == "" {
-		return fmt.Errorf("%s for a category name", util.ErrEmptyString)
+		return util.Category{}, fmt.Errorf("%w for a category name", util.ErrEmptyString)
 	}
 
-	err := s.categoryDb.AddCategory(logger, name, userId)
+	err := s.categoryDb.AddCategory(ctx, logger, name, userId)
 	if err != nil {
-		return err
+		return util.Category{}, err
 	}
 
-	return nil
+	return s.categoryDb.GetCategory(ctx, logger, name, userId)
 }
 
-func (s *CategoryService) GetCategory(logger *slog.Logger, userId uint64, name string) (util.Category, error) {
+func (s *CategoryService) GetCategory(ctx context.Context, logger *slog.Logger, userId uint64, name string) (util.Category, error) {
 	if userId < 1 {
 		return util.Category{}, util.ErrInvalidUserId
 	}
 	if name == "" {
-		return util.Category{}, fmt.Errorf("%s for a category name", util.ErrEmptyString)
+		return util.Category{}, fmt.Errorf("%w for a category name", util.ErrEmptyString)
 	}
 
-	category, err := s.categoryDb.GetCategory(logger, name, userId)
+	category, err := s.categoryDb.GetCategory(ctx, logger, name, userId)
 	if err != nil {
 		return category, err
 	}
@@ -50,15 +51,28 @@ func (s *CategoryService) GetCategory(logger *slog.Logger, userId uint64, name s
 	return category, nil
 }
 
-func (s *CategoryService) QueryCategory(logger *slog.Logger, userId uint64, prefix string) ([]util.Category, error) {
+func (s *CategoryService) GetAllCategories(ctx context.Context, logger *slog.Logger, userId uint64) ([]util.Category, error) {
+	if userId < 1 {
+		return nil, util.ErrInvalidUserId
+	}
+
+	categories, err := s.categoryDb.GetUserCategories(ctx, logger, userId)
+	if err != nil {
+		return categories, err
+	}
+
+	return categories, nil
+}
+
+func (s *CategoryService) QueryCategory(ctx context.Context, logger *slog.Logger, userId uint64, prefix string) ([]util.Category, error) {
 	if userId < 1 {
 		return nil, util.ErrInvalidUserId
 	}
 	if prefix == "" {
-		return nil, fmt.Errorf("%s for a prefix", util.ErrEmptyString)
+		return nil, fmt.Errorf("%w for a prefix", util.ErrEmptyString)
 	}
 
-	categories, err := s.categoryDb.QueryCategory(logger, prefix, userId)
+	categories, err := s.categoryDb.QueryCategory(ctx, logger, prefix, userId)
 	if err != nil {
 		return categories, err
 	}
@@ -66,23 +80,35 @@ func (s *CategoryService) QueryCategory(logger *slog.Logger, userId uint64, pref
 	return categories, nil
 }
 
-func (s *CategoryService) ChangeName(logger *slog.Logger, userId uint64, categoryId uint64, newName string) error {
+func (s *CategoryService) ChangeName(ctx context.Context, logger *slog.Logger, userId uint64, categoryId uint64, newName string) (util.Category, error) {
 	if userId < 1 {
-		return util.ErrInvalidUserId
+		return util.Category{}, util.ErrInvalidUserId
 	}
 	if categoryId < 1 {
-		return util.ErrInvalidCategoryId
+		return util.Category{}, util.ErrInvalidCategoryId
+	}
+	if newName == "" {
+		return util.Category{}, fmt.Errorf("%w for a category name", util.ErrEmptyString)
 	}
 
-	err := s.categoryDb.EditCategoryName(logger, categoryId, newName, userId)
+	category, err := s.categoryDb.GetCategoryById(ctx, logger, categoryId, userId)
 	if err != nil {
-		return err
+		return util.Category{}, err
+	}
+	if category.Name == newName {
+		return category, nil
 	}
 
-	return nil
+	err = s.categoryDb.EditCategoryName(ctx, logger, categoryId, newName, userId)
+	if err != nil {
+		return util.Category{}, err
+	}
+
+	category.Name = newName
+	return category, nil
 }
 
-func (s *CategoryService) DeleteCategory(logger *slog.Logger, userId uint64, categoryId uint64) error {
+func (s *CategoryService) DeleteCategory(ctx context.Context, logger *slog.Logger, userId uint64, categoryId uint64) error {
 	if userId < 1 {
 		return util.ErrInvalidUserId
 	}
@@ -90,7 +116,7 @@ func (s *CategoryService) DeleteCategory(logger *slog.Logger, userId uint64, cat
 		return util.ErrInvalidCategoryId
 	}
 
-	err := s.categoryDb.DeleteCategory(logger, categoryId, userId)
+	err := s.categoryDb.DeleteCategory(ctx, logger, categoryId, userId)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,202 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/NerdBow/Grinders-API/internal/database"
+	"github.com/NerdBow/Grinders-API/internal/util"
+)
+
+// GoalService handles goals on categories, such as working on a category for 10 hours a week
+// or completing 5 of its tasks a week. Each goal type has an evaluator that measures its progress.
+// Periods start at midnight in the time zone of the user, weeks start on Monday.
+type GoalService struct {
+	goalDb     database.GoalsDB
+	categoryDb database.CategoriesDB
+	settingsDb database.UserSettingsDB
+	streakDb   database.StreaksDB
+	evaluators map[util.GoalType]goalEvaluator
+}
+
+func NewGoalService(goalDb database.GoalsDB, categoryDb database.CategoriesDB, pauseDb database.PausesDB, settingsDb database.UserSettingsDB, streakDb database.StreaksDB) GoalService {
+	return GoalService{
+		goalDb:     goalDb,
+		categoryDb: categoryDb,
+		settingsDb: settingsDb,
+		streakDb:   streakDb,
+		evaluators: newGoalEvaluators(goalDb, pauseDb),
+	}
+}
+
+// CreateGoal creates a goal on a category of the user.
+func (s *GoalService) CreateGoal(ctx context.Context, logger *slog.Logger, userId uint64, goal util.Goal) (util.Goal, error) {
+	if userId < 1 {
+		return util.Goal{}, util.ErrInvalidUserId
+	}
+
+	err := s.checkGoal(ctx, logger, userId, goal)
+	if err != nil {
+		return util.Goal{}, err
+	}
+
+	goal.Id, err = s.goalDb.AddGoal(ctx, logger, goal)
+	if err != nil {
+		return util.Goal{}, err
+	}
+
+	return goal, nil
+}
+
+func (s *GoalService) GetGoal(ctx context.Context, logger *slog.Logger, userId uint64, goalId uint64) (util.Goal, error) {
+	if userId < 1 {
+		return util.Goal{}, util.ErrInvalidUserId
+	}
+	if goalId < 1 {
+		return util.Goal{}, util.ErrInvalidGoalId
+	}
+
+	return s.goalDb.GetGoal(ctx, logger, goalId, userId)
+}
+
+func (s *GoalService) GetAllGoals(ctx context.Context, logger *slog.Logger, userId uint64) ([]util.Goal, error) {
+	if userId < 1 {
+		return nil, util.ErrInvalidUserId
+	}
+
+	return s.goalDb.GetUserGoals(ctx, logger, userId)
+}
+
+// EditGoal changes the non zero fields of the goal.
+func (s *GoalService) EditGoal(ctx context.Context, logger *slog.Logger, userId uint64, goal util.Goal) (util.Goal, error) {
+	oldGoal, err := s.GetGoal(ctx, logger, userId, goal.Id)
+	if err != nil {
+		return util.Goal{}, err
+	}
+
+	if goal.CategoryId == 0 {
+		goal.CategoryId = oldGoal.CategoryId
+	}
+	if goal.Value == 0 {
+		goal.Value = oldGoal.Value
+	}
+	if goal.Type == 0 {
+		goal.Type = oldGoal.Type
+	}
+
+	err = s.checkGoal(ctx, logger, userId, goal)
+	if err != nil {
+		return util.Goal{}, err
+	}
+
+	err = s.goalDb.EditGoal(ctx, logger, goal, userId)
+	if err != nil {
+		return util.Goal{}, err
+	}
+
+	err = s.streakDb.DeleteGoalStreak(ctx, logger, goal.Id)
+	if err != nil {
+		return util.Goal{}, err
+	}
+
+	return goal, nil
+}
+
+func (s *GoalService) DeleteGoal(ctx context.Context, logger *slog.Logger, userId uint64, goalId uint64) error {
+	if userId < 1 {
+		return util.ErrInvalidUserId
+	}
+	if goalId < 1 {
+		return util.ErrInvalidGoalId
+	}
+
+	err := s.goalDb.DeleteGoal(ctx, logger, goalId, userId)
+	if err != nil {
+		return err
+	}
+
+	return s.streakDb.DeleteGoalStreak(ctx, logger, goalId)
+}
+
+// GetProgress returns how far the current period of the goal is done and where it is projected to end up.
+func (s *GoalService) GetProgress(ctx context.Context, logger *slog.Logger, userId uint64, goalId uint64) (util.GoalProgress, error) {
+	goal, err := s.GetGoal(ctx, logger, userId, goalId)
+	if err != nil {
+		return util.GoalProgress{}, err
+	}
+
+	loc, err := userLocation(ctx, logger, s.settingsDb, userId)
+	if err != nil {
+		return util.GoalProgress{}, err
+	}
+
+	return s.progress(ctx, logger, userId, goal, time.Now().In(loc))
+}
+
+// GetAllProgress returns the progress of every goal of the user.
+func (s *GoalService) GetAllProgress(ctx context.Context, logger *slog.Logger, userId uint64) ([]util.GoalProgress, error) {
+	goals, err := s.GetAllGoals(ctx, logger, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	loc, err := userLocation(ctx, logger, s.settingsDb, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().In(loc)
+	progresses := make([]util.GoalProgress, 0, len(goals))
+	for _, goal := range goals {
+		progress, err := s.progress(ctx, logger, userId, goal, now)
+		if err != nil {
+			return nil, err
+		}
+		progresses = append(progresses, progress)
+	}
+
+	return progresses, nil
+}
+
+// progress evaluates the period of the goal containing now, which is in the location of the user.
+func (s *GoalService) progress(ctx context.Context, logger *slog.Logger, userId uint64, goal util.Goal, now time.Time) (util.GoalProgress, error) {
+	evaluator, ok := s.evaluators[goal.Type]
+	if !ok {
+		return util.GoalProgress{}, util.ErrInvalidGoal
+	}
+
+	from, to := periodBounds(evaluator.period(), now)
+	from, to, now = from.UTC(), to.UTC(), now.UTC()
+
+	progress, projected, err := evaluator.evaluate(ctx, logger, userId, goal, from, to, now)
+	if err != nil {
+		return util.GoalProgress{}, err
+	}
+
+	return util.GoalProgress{
+		Goal:        goal,
+		PeriodStart: from,
+		PeriodEnd:   to,
+		Progress:    progress,
+		Percent:     progress * 100 / goal.Value,
+		Projected:   projected,
+		IsComplete:  progress >= goal.Value,
+		OnTrack:     projected >= goal.Value,
+	}, nil
+}
+
+// checkGoal checks that the goal has a known type, a value its evaluator can reach and is on a category of the user.
+func (s *GoalService) checkGoal(ctx context.Context, logger *slog.Logger, userId uint64, goal util.Goal) error {
+	if goal.CategoryId < 1 {
+		return util.ErrInvalidCategoryId
+	}
+
+	evaluator, ok := s.evaluators[goal.Type]
+	if !ok || goal.Value < 1 || goal.Value > evaluator.maxValue() {
+		return util.ErrInvalidGoal
+	}
+
+	_, err := s.categoryDb.GetCategoryById(ctx, logger, goal.CategoryId, userId)
+	return err
+}
@@ -0,0 +1,185 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/NerdBow/Grinders-API/internal/database"
+	"github.com/NerdBow/Grinders-API/internal/util"
+)
+
+const (
+	MAX_GOAL_TASKS   = 1000 // Most completed tasks a task goal can target in one period
+	MAX_GOAL_PERCENT = 100
+)
+
+// goalEvaluator measures the progress of one kind of goal.
+type goalEvaluator interface {
+	// period returns the period the goal is measured over.
+	period() string
+	// maxValue returns the largest value a goal can target.
+	maxValue() int64
+	// evaluate returns the progress of the goal over the period from to as of now, which is not after to,
+	// and the progress it is projected to have at the end of the period.
+	evaluate(ctx context.Context, logger *slog.Logger, userId uint64, goal util.Goal, from time.Time, to time.Time, now time.Time) (int64, int64, error)
+}
+
+// newGoalEvaluators returns the evaluator of every goal type.
+func newGoalEvaluators(goalDb database.GoalsDB, pauseDb database.PausesDB) map[util.GoalType]goalEvaluator {
+	return map[util.GoalType]goalEvaluator{
+		util.GOAL_DAILY_TIME:        timeGoalEvaluator{util.PERIOD_DAY, goalDb, pauseDb},
+		util.GOAL_WEEKLY_TIME:       timeGoalEvaluator{util.PERIOD_WEEK, goalDb, pauseDb},
+		util.GOAL_MONTHLY_TIME:      timeGoalEvaluator{util.PERIOD_MONTH, goalDb, pauseDb},
+		util.GOAL_DAILY_TASKS:       taskGoalEvaluator{util.PERIOD_DAY, goalDb},
+		util.GOAL_WEEKLY_TASKS:      taskGoalEvaluator{util.PERIOD_WEEK, goalDb},
+		util.GOAL_MONTHLY_TASKS:     taskGoalEvaluator{util.PERIOD_MONTH, goalDb},
+		util.GOAL_DAILY_DEADLINES:   deadlineGoalEvaluator{util.PERIOD_DAY, goalDb},
+		util.GOAL_WEEKLY_DEADLINES:  deadlineGoalEvaluator{util.PERIOD_WEEK, goalDb},
+		util.GOAL_MONTHLY_DEADLINES: deadlineGoalEvaluator{util.PERIOD_MONTH, goalDb},
+	}
+}
+
+// timeGoalEvaluator measures the focused seconds of the work logs on the category of the goal.
+// Running work logs are counted up to now.
+type timeGoalEvaluator struct {
+	periodName string
+	goalDb     database.GoalsDB
+	pauseDb    database.PausesDB
+}
+
+func (e timeGoalEvaluator) period() string {
+	return e.periodName
+}
+
+func (e timeGoalEvaluator) maxValue() int64 {
+	return periodMaxSeconds[e.periodName]
+}
+
+func (e timeGoalEvaluator) evaluate(ctx context.Context, logger *slog.Logger, userId uint64, goal util.Goal, from time.Time, to time.Time, now time.Time) (int64, int64, error) {
+	workLogs, err := e.goalDb.GetCategoryWorkLogs(ctx, logger, userId, goal.CategoryId, from, now)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	progress := int64(0)
+	for _, workLog := range workLogs {
+		pauses, err := e.pauseDb.GetPauses(ctx, logger, workLog.Id)
+		if err != nil {
+			return 0, 0, err
+		}
+
+		start := workLog.StartTime
+		if start.Before(from) {
+			start = from
+		}
+		end := workLog.EndTime
+		if !workLog.IsComplete || end.After(now) {
+			end = now
+		}
+		if end.After(start) {
+			progress += focusedDuration(start, end, pauses)
+		}
+	}
+
+	return progress, paceProjection(progress, from, to, now), nil
+}
+
+// taskGoalEvaluator measures the tasks in the category of the goal that were completed in the period.
+type taskGoalEvaluator struct {
+	periodName string
+	goalDb     database.GoalsDB
+}
+
+func (e taskGoalEvaluator) period() string {
+	return e.periodName
+}
+
+func (e taskGoalEvaluator) maxValue() int64 {
+	return MAX_GOAL_TASKS
+}
+
+func (e taskGoalEvaluator) evaluate(ctx context.Context, logger *slog.Logger, userId uint64, goal util.Goal, from time.Time, to time.Time, now time.Time) (int64, int64, error) {
+	progress, err := e.goalDb.CountCompletedTasks(ctx, logger, userId, goal.CategoryId, from, now)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return progress, paceProjection(progress, from, to, now), nil
+}
+
+// deadlineGoalEvaluator measures the percentage of the tasks in the category of the goal that are due in the period
+// and were completed before their deadline. A period without due tasks has no missed deadlines, so it is at 100 percent.
+// The tasks that are not due yet are projected to be completed in time at the same rate as the tasks that are.
+type deadlineGoalEvaluator struct {
+	periodName string
+	goalDb     database.GoalsDB
+}
+
+func (e deadlineGoalEvaluator) period() string {
+	return e.periodName
+}
+
+func (e deadlineGoalEvaluator) maxValue() int64 {
+	return MAX_GOAL_PERCENT
+}
+
+func (e deadlineGoalEvaluator) evaluate(ctx context.Context, logger *slog.Logger, userId uint64, goal util.Goal, from time.Time, to time.Time, now time.Time) (int64, int64, error) {
+	tasks, err := e.goalDb.GetDueTasks(ctx, logger, userId, goal.CategoryId, from, to)
+	if err != nil {
+		return 0, 0, err
+	}
+	if len(tasks) == 0 {
+		return MAX_GOAL_PERCENT, MAX_GOAL_PERCENT, nil
+	}
+
+	hit, decided := int64(0), int64(0)
+	for _, task := range tasks {
+		switch {
+		case task.IsComplete && !task.CompletionTime.After(task.DeadlineTime):
+			hit++
+			decided++
+		case task.IsComplete || !task.DeadlineTime.After(now):
+			decided++
+		}
+	}
+
+	progress := hit * MAX_GOAL_PERCENT / int64(len(tasks))
+	projected := int64(MAX_GOAL_PERCENT)
+	if decided > 0 {
+		projected = hit * MAX_GOAL_PERCENT / decided
+	}
+	return progress, projected, nil
+}
+
+// paceProjection returns the progress at to if it keeps growing at the pace it had from from to now.
+func paceProjection(progress int64, from time.Time, to time.Time, now time.Time) int64 {
+	elapsed := now.Sub(from)
+	if elapsed <= 0 || !now.Before(to) {
+		return progress
+	}
+	return int64(float64(progress) * float64(to.Sub(from)) / float64(elapsed))
+}
+
+// periodMaxSeconds are the most seconds a period can have.
+var periodMaxSeconds = map[string]int64{
+	util.PERIOD_DAY:   24 * 60 * 60,
+	util.PERIOD_WEEK:  7 * 24 * 60 * 60,
+	util.PERIOD_MONTH: 31 * 24 * 60 * 60,
+}
+
+// periodBounds returns the start and end of the period containing t, in the location of t.
+func periodBounds(period string, t time.Time) (time.Time, time.Time) {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+
+	switch period {
+	case util.PERIOD_WEEK:
+		monday := day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+		return monday, monday.AddDate(0, 0, 7)
+	case util.PERIOD_MONTH:
+		first := day.AddDate(0, 0, 1-day.Day())
+		return first, first.AddDate(0, 1, 0)
+	default:
+		return day, day.AddDate(0, 0, 1)
+	}
+}
@@ -0,0 +1,442 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/NerdBow/Grinders-API/internal/auth"
+	"github.com/NerdBow/Grinders-API/internal/database"
+	"github.com/NerdBow/Grinders-API/internal/util"
+)
+
+const (
+	INVITE_CODE_LENGTH    = 16 // Random bytes in an invite code
+	DEFAULT_INVITE_EXPIRY = 7 * 24 * time.Hour
+	MAX_INVITE_EXPIRY     = 30 * 24 * time.Hour
+	DEFAULT_MEMBER_TIME   = 7 * 24 * time.Hour // Range of member time that is returned when no start is given
+)
+
+// GroupService handles groups of users that track their time together.
+// What a member can do in a group is decided by the permissions of the member's role, see authorize.
+type GroupService struct {
+	groupDb       database.GroupsDB
+	memberDb      database.GroupMembersDB
+	inviteDb      database.GroupInvitesDB
+	categoryDb    database.GroupCategoriesDB
+	leaderboardDb database.LeaderboardsDB
+	userDb        database.UsersDB
+	settingsDb    database.UserSettingsDB
+}
+
+func NewGroupService(groupDb database.GroupsDB, memberDb database.GroupMembersDB, inviteDb database.GroupInvitesDB, categoryDb database.GroupCategoriesDB, leaderboardDb database.LeaderboardsDB, userDb database.UsersDB, settingsDb database.UserSettingsDB) GroupService {
+	return GroupService{
+		groupDb:       groupDb,
+		memberDb:      memberDb,
+		inviteDb:      inviteDb,
+		categoryDb:    categoryDb,
+		leaderboardDb: leaderboardDb,
+		userDb:        userDb,
+		settingsDb:    settingsDb,
+	}
+}
+
+// CreateGroup creates a group owned by the user, who becomes its first member.
+func (s *GroupService) CreateGroup(ctx context.Context, logger *slog.Logger, userId uint64, name string) (util.Group, error) {
+	if userId < 1 {
+		return util.Group{}, util.ErrInvalidUserId
+	}
+	if name == "" {
+		return util.Group{}, fmt.Errorf("%w for a group name", util.ErrEmptyString)
+	}
+
+	group := util.Group{Name: name, OwnerId: userId}
+	id, err := s.groupDb.AddGroup(ctx, logger, group)
+	if err != nil {
+		return util.Group{}, err
+	}
+
+	group.Id = id
+	return group, nil
+}
+
+// GetGroup returns the group if the user is a member of it.
+func (s *GroupService) GetGroup(ctx context.Context, logger *slog.Logger, userId uint64, groupId uint64) (util.Group, error) {
+	if userId < 1 {
+		return util.Group{}, util.ErrInvalidUserId
+	}
+	if groupId < 1 {
+		return util.Group{}, util.ErrInvalidGroupId
+	}
+
+	return s.groupDb.GetGroup(ctx, logger, groupId, userId)
+}
+
+func (s *GroupService) GetAllGroups(ctx context.Context, logger *slog.Logger, userId uint64) ([]util.Group, error) {
+	if userId < 1 {
+		return nil, util.ErrInvalidUserId
+	}
+
+	return s.groupDb.GetUserGroups(ctx, logger, userId)
+}
+
+func (s *GroupService) ChangeName(ctx context.Context, logger *slog.Logger, userId uint64, groupId uint64, newName string) (util.Group, error) {
+	if newName == "" {
+		return util.Group{}, fmt.Errorf("%w for a group name", util.ErrEmptyString)
+	}
+
+	_, err := s.authorize(ctx, logger, userId, groupId, PERMISSION_MANAGE_GROUP)
+	if err != nil {
+		return util.Group{}, err
+	}
+
+	group, err := s.groupDb.GetGroup(ctx, logger, groupId, userId)
+	if err != nil {
+		return util.Group{}, err
+	}
+
+	err = s.groupDb.EditGroupName(ctx, logger, groupId, newName, userId)
+	if err != nil {
+		return util.Group{}, err
+	}
+
+	group.Name = newName
+	return group, nil
+}
+
+// DeleteGroup deletes the group and removes all of its members, invites and shared categories.
+func (s *GroupService) DeleteGroup(ctx context.Context, logger *slog.Logger, userId uint64, groupId uint64) error {
+	_, err := s.authorize(ctx, logger, userId, groupId, PERMISSION_MANAGE_GROUP)
+	if err != nil {
+		return err
+	}
+
+	return s.groupDb.DeleteGroup(ctx, logger, groupId, userId)
+}
+
+// GetMembers returns the members of the group if the user is one of them.
+func (s *GroupService) GetMembers(ctx context.Context, logger *slog.Logger, userId uint64, groupId uint64) ([]util.GroupMember, error) {
+	_, err := s.authorize(ctx, logger, userId, groupId, PERMISSION_VIEW)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.memberDb.GetGroupMembers(ctx, logger, groupId)
+}
+
+// AddMember adds the user with the username to the group as a member.
+func (s *GroupService) AddMember(ctx context.Context, logger *slog.Logger, userId uint64, groupId uint64, username string) (util.GroupMember, error) {
+	if username == "" {
+		return util.GroupMember{}, fmt.Errorf("%w for a username", util.ErrEmptyString)
+	}
+
+	_, err := s.authorize(ctx, logger, userId, groupId, PERMISSION_INVITE)
+	if err != nil {
+		return util.GroupMember{}, err
+	}
+
+	user, err := s.userDb.GetUserByUsername(ctx, logger, username)
+	if err != nil {
+		return util.GroupMember{}, err
+	}
+
+	member := util.GroupMember{GroupId: groupId, UserId: user.Id, Username: user.Username, Role: util.ROLE_MEMBER}
+	member.Id, err = s.memberDb.AddGroupMember(ctx, logger, member)
+	if err != nil {
+		return util.GroupMember{}, err
+	}
+
+	return member, nil
+}
+
+// RemoveMember removes the member from the group. Only members with a lower role than the user can be removed and the owner never can.
+func (s *GroupService) RemoveMember(ctx context.Context, logger *slog.Logger, userId uint64, groupId uint64, memberId uint64) error {
+	if memberId < 1 {
+		return util.ErrInvalidUserId
+	}
+
+	remover, err := s.authorize(ctx, logger, userId, groupId, PERMISSION_REMOVE_MEMBERS)
+	if err != nil {
+		return err
+	}
+
+	member, err := s.memberDb.GetGroupMember(ctx, logger, groupId, memberId)
+	if err != nil {
+		return err
+	}
+	if member.Role == util.ROLE_OWNER {
+		return util.ErrOwnerCannotLeave
+	}
+	if member.Role >= remover.Role {
+		return util.ErrGroupPermission
+	}
+
+	return s.memberDb.DeleteGroupMember(ctx, logger, groupId, memberId)
+}
+
+// SetRole promotes or demotes the member of the group to the role, which can not be the owner role.
+// Ownership is given away with TransferOwnership instead.
+func (s *GroupService) SetRole(ctx context.Context, logger *slog.Logger, userId uint64, groupId uint64, memberId uint64, role util.GroupRole) (util.GroupMember, error) {
+	if memberId < 1 {
+		return util.GroupMember{}, util.ErrInvalidUserId
+	}
+	if role < util.ROLE_VIEWER || role > util.ROLE_ADMIN {
+		return util.GroupMember{}, util.ErrInvalidRole
+	}
+
+	_, err := s.authorize(ctx, logger, userId, groupId, PERMISSION_MANAGE_ROLES)
+	if err != nil {
+		return util.GroupMember{}, err
+	}
+
+	member, err := s.memberDb.GetGroupMember(ctx, logger, groupId, memberId)
+	if err != nil {
+		return util.GroupMember{}, err
+	}
+	if member.Role == util.ROLE_OWNER {
+		return util.GroupMember{}, util.ErrOwnerRole
+	}
+
+	err = s.memberDb.SetMemberRole(ctx, logger, groupId, memberId, role)
+	if err != nil {
+		return util.GroupMember{}, err
+	}
+
+	member.Role = role
+	return member, nil
+}
+
+// TransferOwnership makes the member newOwnerId the owner of the group owned by the user, who becomes an admin.
+func (s *GroupService) TransferOwnership(ctx context.Context, logger *slog.Logger, userId uint64, groupId uint64, newOwnerId uint64) (util.Group, error) {
+	if newOwnerId < 1 {
+		return util.Group{}, util.ErrInvalidUserId
+	}
+	if newOwnerId == userId {
+		return util.Group{}, util.ErrInvalidTransfer
+	}
+
+	_, err := s.authorize(ctx, logger, userId, groupId, PERMISSION_MANAGE_GROUP)
+	if err != nil {
+		return util.Group{}, err
+	}
+
+	err = s.groupDb.TransferGroupOwnership(ctx, logger, groupId, userId, newOwnerId)
+	if errors.Is(err, util.ErrMemberNotFound) {
+		return util.Group{}, util.ErrInvalidTransfer
+	}
+	if err != nil {
+		return util.Group{}, err
+	}
+
+	return s.groupDb.GetGroup(ctx, logger, groupId, userId)
+}
+
+// GetMemberTime returns the focused time of the member of the group from from up to to.
+// A zero to is now and a zero from is DEFAULT_MEMBER_TIME before to. Work logs that overlap the range only count the time inside it.
+// Members that opted out of leaderboards keep their time private as well, ErrGroupPermission is returned for them.
+func (s *GroupService) GetMemberTime(ctx context.Context, logger *slog.Logger, userId uint64, groupId uint64, memberId uint64, from time.Time, to time.Time) (util.MemberTime, error) {
+	if memberId < 1 {
+		return util.MemberTime{}, util.ErrInvalidUserId
+	}
+	if to.IsZero() {
+		to = time.Now().UTC()
+	}
+	if from.IsZero() {
+		from = to.Add(-DEFAULT_MEMBER_TIME)
+	}
+	if !from.Before(to) {
+		return util.MemberTime{}, util.ErrInvalidTimeRange
+	}
+
+	_, err := s.authorize(ctx, logger, userId, groupId, PERMISSION_VIEW_TIME)
+	if err != nil {
+		return util.MemberTime{}, err
+	}
+
+	_, err = s.memberDb.GetGroupMember(ctx, logger, groupId, memberId)
+	if err != nil {
+		return util.MemberTime{}, err
+	}
+
+	optedOut, err := s.leaderboardDb.GetLeaderboardOptOut(ctx, logger, memberId)
+	if err != nil {
+		return util.MemberTime{}, err
+	}
+	if optedOut && memberId != userId {
+		return util.MemberTime{}, util.ErrGroupPermission
+	}
+
+	// Running work logs count up to now, so the time after now is left out.
+	until := to
+	if now := time.Now().UTC(); until.After(now) {
+		until = now
+	}
+	duration, err := s.memberDb.GetMemberFocusTime(ctx, logger, memberId, from.UTC(), until.UTC())
+	if err != nil {
+		return util.MemberTime{}, err
+	}
+
+	return util.MemberTime{UserId: memberId, From: from.UTC(), To: to.UTC(), Duration: duration}, nil
+}
+
+// GetCategories returns the shared categories of the group.
+func (s *GroupService) GetCategories(ctx context.Context, logger *slog.Logger, userId uint64, groupId uint64) ([]util.GroupCategory, error) {
+	_, err := s.authorize(ctx, logger, userId, groupId, PERMISSION_VIEW)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.categoryDb.GetGroupCategories(ctx, logger, groupId)
+}
+
+// CreateCategory creates a shared category in the group.
+func (s *GroupService) CreateCategory(ctx context.Context, logger *slog.Logger, userId uint64, groupId uint64, name string) (util.GroupCategory, error) {
+	if name == "" {
+		return util.GroupCategory{}, fmt.Errorf("%w for a category name", util.ErrEmptyString)
+	}
+
+	_, err := s.authorize(ctx, logger, userId, groupId, PERMISSION_EDIT_CATEGORIES)
+	if err != nil {
+		return util.GroupCategory{}, err
+	}
+
+	category := util.GroupCategory{Name: name, GroupId: groupId}
+	category.Id, err = s.categoryDb.AddGroupCategory(ctx, logger, category)
+	if err != nil {
+		return util.GroupCategory{}, err
+	}
+
+	return category, nil
+}
+
+// RenameCategory changes the name of the shared category of the group.
+func (s *GroupService) RenameCategory(ctx context.Context, logger *slog.Logger, userId uint64, groupId uint64, categoryId uint64, newName string) (util.GroupCategory, error) {
+	if categoryId < 1 {
+		return util.GroupCategory{}, util.ErrGroupCategoryId
+	}
+	if newName == "" {
+		return util.GroupCategory{}, fmt.Errorf("%w for a category name", util.ErrEmptyString)
+	}
+
+	_, err := s.authorize(ctx, logger, userId, groupId, PERMISSION_EDIT_CATEGORIES)
+	if err != nil {
+		return util.GroupCategory{}, err
+	}
+
+	err = s.categoryDb.EditGroupCategoryName(ctx, logger, categoryId, newName, groupId)
+	if err != nil {
+		return util.GroupCategory{}, err
+	}
+
+	return util.GroupCategory{Id: categoryId, Name: newName, GroupId: groupId}, nil
+}
+
+// DeleteCategory deletes the shared category of the group.
+func (s *GroupService) DeleteCategory(ctx context.Context, logger *slog.Logger, userId uint64, groupId uint64, categoryId uint64) error {
+	if categoryId < 1 {
+		return util.ErrGroupCategoryId
+	}
+
+	_, err := s.authorize(ctx, logger, userId, groupId, PERMISSION_EDIT_CATEGORIES)
+	if err != nil {
+		return err
+	}
+
+	return s.categoryDb.DeleteGroupCategory(ctx, logger, categoryId, groupId)
+}
+
+// LeaveGroup removes the user from the group. The owner has to delete the group instead.
+func (s *GroupService) LeaveGroup(ctx context.Context, logger *slog.Logger, userId uint64, groupId uint64) error {
+	group, err := s.GetGroup(ctx, logger, userId, groupId)
+	if err != nil {
+		return err
+	}
+	if userId == group.OwnerId {
+		return util.ErrOwnerCannotLeave
+	}
+
+	return s.memberDb.DeleteGroupMember(ctx, logger, groupId, userId)
+}
+
+// CreateInvite creates an invite to the group that can be used maxUses times, or without limit if 0.
+// The invite expires after expiresIn, or DEFAULT_INVITE_EXPIRY if it is 0.
+// The returned invite is the only place the code can be read from.
+func (s *GroupService) CreateInvite(ctx context.Context, logger *slog.Logger, userId uint64, groupId uint64, maxUses uint32, expiresIn time.Duration) (util.GroupInvite, error) {
+	if expiresIn == 0 {
+		expiresIn = DEFAULT_INVITE_EXPIRY
+	}
+	if expiresIn < 0 || expiresIn > MAX_INVITE_EXPIRY {
+		return util.GroupInvite{}, util.ErrInvalidInvite
+	}
+
+	_, err := s.authorize(ctx, logger, userId, groupId, PERMISSION_INVITE)
+	if err != nil {
+		return util.GroupInvite{}, err
+	}
+
+	code, err := auth.GenerateToken(INVITE_CODE_LENGTH)
+	if err != nil {
+		logger.LogAttrs(ctx, slog.LevelError, "GenerateToken CreateInvite", slog.String("err", err.Error()))
+		return util.GroupInvite{}, util.ErrInternal
+	}
+
+	now := time.Now().UTC()
+	invite := util.GroupInvite{
+		GroupId:        groupId,
+		Code:           code,
+		HashedCode:     auth.HashToken(code),
+		MaxUses:        maxUses,
+		ExpirationTime: now.Add(expiresIn),
+		CreationTime:   now,
+	}
+
+	invite.Id, err = s.inviteDb.AddGroupInvite(ctx, logger, invite)
+	if err != nil {
+		return util.GroupInvite{}, err
+	}
+
+	return invite, nil
+}
+
+// GetInvites returns the invites of the group that can still be used.
+func (s *GroupService) GetInvites(ctx context.Context, logger *slog.Logger, userId uint64, groupId uint64) ([]util.GroupInvite, error) {
+	_, err := s.authorize(ctx, logger, userId, groupId, PERMISSION_INVITE)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.inviteDb.GetGroupInvites(ctx, logger, groupId, time.Now().UTC())
+}
+
+// RevokeInvite deletes the invite of the group, so its code can no longer be used.
+func (s *GroupService) RevokeInvite(ctx context.Context, logger *slog.Logger, userId uint64, groupId uint64, inviteId uint64) error {
+	if inviteId < 1 {
+		return util.ErrInvalidInviteId
+	}
+
+	_, err := s.authorize(ctx, logger, userId, groupId, PERMISSION_INVITE)
+	if err != nil {
+		return err
+	}
+
+	return s.inviteDb.DeleteGroupInvite(ctx, logger, inviteId, groupId)
+}
+
+// JoinGroup adds the user to the group of the invite with the code.
+func (s *GroupService) JoinGroup(ctx context.Context, logger *slog.Logger, userId uint64, code string) (util.Group, error) {
+	if userId < 1 {
+		return util.Group{}, util.ErrInvalidUserId
+	}
+	if code == "" {
+		return util.Group{}, fmt.Errorf("%w for an invite code", util.ErrEmptyString)
+	}
+
+	groupId, err := s.inviteDb.RedeemGroupInvite(ctx, logger, auth.HashToken(code), userId, time.Now().UTC())
+	if err != nil {
+		return util.Group{}, err
+	}
+
+	return s.groupDb.GetGroup(ctx, logger, groupId, userId)
+}
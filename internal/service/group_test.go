@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/NerdBow/Grinders-API/internal/util"
+)
+
+func TestFocusTime(t *testing.T) {
+	ctx := context.Background()
+	logger := testLogger()
+	_, db := newWorkLogTestService(t)
+
+	addCompleteWorkLog(t, db, 1, "before", clock(7, 0), clock(8, 0), nil)
+	addCompleteWorkLog(t, db, 1, "across the start", clock(8, 30), clock(9, 30), []span{{clock(8, 40), clock(8, 50)}})
+	addCompleteWorkLog(t, db, 2, "inside", clock(10, 0), clock(11, 0), []span{{clock(10, 10), clock(10, 20)}})
+
+	// The running work log is paused since 12:10 and now is 12:15.
+	runningId, err := db.AddWorkLog(ctx, logger, util.WorkLog{TaskId: 1, Objective: "running", StartTime: clock(12, 0), UserId: 1})
+	if err != nil {
+		t.Fatalf("AddWorkLog returned %v", err)
+	}
+	if _, err := db.AddPause(ctx, logger, util.Pause{StartTime: clock(12, 10), WorkLogId: runningId}); err != nil {
+		t.Fatalf("AddPause returned %v", err)
+	}
+	now := clock(12, 15)
+
+	groupId, err := db.AddGroup(ctx, logger, util.Group{Name: "group", OwnerId: 1})
+	if err != nil {
+		t.Fatalf("AddGroup returned %v", err)
+	}
+
+	tests := []struct {
+		name string
+		from time.Time
+		to   time.Time
+		want int64
+	}{
+		{
+			name: "clips work logs to the range",
+			from: clock(9, 0),
+			to:   now,
+			want: int64((30*time.Minute + 50*time.Minute + 10*time.Minute) / time.Second),
+		},
+		{
+			name: "clips pauses to the range",
+			from: clock(10, 15),
+			to:   clock(10, 45),
+			want: int64(25 * time.Minute / time.Second),
+		},
+		{
+			name: "counts running work logs up to now",
+			from: clock(12, 5),
+			to:   now,
+			want: int64(5 * time.Minute / time.Second),
+		},
+		{
+			name: "nothing focused",
+			from: clock(11, 0),
+			to:   clock(12, 0),
+			want: 0,
+		},
+	}
+
+	evaluator := timeGoalEvaluator{util.PERIOD_DAY, db, db}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := db.GetMemberFocusTime(ctx, logger, 1, test.from, test.to)
+			if err != nil {
+				t.Fatalf("GetMemberFocusTime returned %v", err)
+			}
+			if got != test.want {
+				t.Errorf("GetMemberFocusTime = %d, want %d", got, test.want)
+			}
+
+			entries, err := db.GetLeaderboard(ctx, logger, groupId, test.from, test.to)
+			if err != nil {
+				t.Fatalf("GetLeaderboard returned %v", err)
+			}
+			if len(entries) != 1 || entries[0].FocusedDuration != test.want {
+				t.Errorf("GetLeaderboard = %+v, want one entry focused for %d", entries, test.want)
+			}
+
+			// Both tasks are in category 1, so the goal progress covers all work logs.
+			progress, _, err := evaluator.evaluate(ctx, logger, 1, util.Goal{CategoryId: 1}, test.from, test.to, test.to)
+			if err != nil {
+				t.Fatalf("evaluate returned %v", err)
+			}
+			if got != progress {
+				t.Errorf("GetMemberFocusTime = %d, but the goal progress is %d", got, progress)
+			}
+		})
+	}
+}
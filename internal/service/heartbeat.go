@@ -0,0 +1,167 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/NerdBow/Grinders-API/internal/database"
+	"github.com/NerdBow/Grinders-API/internal/util"
+)
+
+const (
+	HEARTBEAT_SWEEP_INTERVAL = 30 * time.Second // How often the background sweeper pauses the work logs of idle users
+	DEFAULT_IDLE_THRESHOLD   = 5 * 60           // In seconds
+	MIN_IDLE_THRESHOLD       = 60               // In seconds
+	MAX_IDLE_THRESHOLD       = 24 * 60 * 60     // In seconds
+)
+
+// HeartbeatService tracks when clients last saw the user active and pauses the running work log of idle users.
+// Users who never send a heartbeat are never considered idle.
+type HeartbeatService struct {
+	heartbeatDb database.HeartbeatsDB
+	workLogDb   database.WorkLogsDB
+	pauseDb     database.PausesDB
+	streakDb    database.StreaksDB
+}
+
+func NewHeartbeatService(heartbeatDb database.HeartbeatsDB, workLogDb database.WorkLogsDB, pauseDb database.PausesDB, streakDb database.StreaksDB) HeartbeatService {
+	return HeartbeatService{
+		heartbeatDb: heartbeatDb,
+		workLogDb:   workLogDb,
+		pauseDb:     pauseDb,
+		streakDb:    streakDb,
+	}
+}
+
+// Beat records that the user is active now.
+func (s *HeartbeatService) Beat(ctx context.Context, logger *slog.Logger, userId uint64) (util.Heartbeat, error) {
+	heartbeat, err := s.GetHeartbeat(ctx, logger, userId)
+	if err != nil {
+		return util.Heartbeat{}, err
+	}
+
+	heartbeat.LastHeartbeat = time.Now().UTC()
+	return s.setHeartbeat(ctx, logger, heartbeat)
+}
+
+// GetHeartbeat returns the last heartbeat of the user and their idle threshold.
+func (s *HeartbeatService) GetHeartbeat(ctx context.Context, logger *slog.Logger, userId uint64) (util.Heartbeat, error) {
+	if userId < 1 {
+		return util.Heartbeat{}, util.ErrInvalidUserId
+	}
+
+	heartbeat, err := s.heartbeatDb.GetHeartbeat(ctx, logger, userId)
+	if err != nil {
+		return util.Heartbeat{}, err
+	}
+	if heartbeat.IdleThreshold == 0 {
+		heartbeat.IdleThreshold = DEFAULT_IDLE_THRESHOLD
+	}
+
+	return heartbeat, nil
+}
+
+// SetIdleThreshold sets after how many seconds without a heartbeat the user is idle.
+func (s *HeartbeatService) SetIdleThreshold(ctx context.Context, logger *slog.Logger, userId uint64, threshold int64) (util.Heartbeat, error) {
+	if threshold < MIN_IDLE_THRESHOLD || threshold > MAX_IDLE_THRESHOLD {
+		return util.Heartbeat{}, util.ErrInvalidThreshold
+	}
+
+	heartbeat, err := s.GetHeartbeat(ctx, logger, userId)
+	if err != nil {
+		return util.Heartbeat{}, err
+	}
+
+	heartbeat.IdleThreshold = threshold
+	return s.setHeartbeat(ctx, logger, heartbeat)
+}
+
+// PauseIdleWorkLogs pauses the running work log of every idle user.
+// The pause is backdated to when the user became idle: the last heartbeat, or the start
+// or last resume of the work log if that was later.
+// Failures are logged and do not stop the other work logs from being paused.
+func (s *HeartbeatService) PauseIdleWorkLogs(ctx context.Context, logger *slog.Logger) {
+	now := time.Now().UTC()
+	heartbeats, err := s.heartbeatDb.GetIdleHeartbeats(ctx, logger, now)
+	if err != nil {
+		return
+	}
+
+	for _, heartbeat := range heartbeats {
+		err = s.pauseIdleWorkLog(ctx, logger, heartbeat, now)
+		if err != nil {
+			logger.LogAttrs(ctx, slog.LevelWarn, "PauseIdleWorkLogs", slog.Uint64("userId", heartbeat.UserId), slog.String("err", err.Error()))
+		}
+	}
+}
+
+// pauseIdleWorkLog pauses the running work log of the user of the heartbeat if they have been idle since before now.
+func (s *HeartbeatService) pauseIdleWorkLog(ctx context.Context, logger *slog.Logger, heartbeat util.Heartbeat, now time.Time) error {
+	workLog, err := s.workLogDb.GetRunningWorkLog(ctx, logger, heartbeat.UserId)
+	if errors.Is(err, util.ErrWorkLogNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	pauses, err := s.pauseDb.GetPauses(ctx, logger, workLog.Id)
+	if err != nil {
+		return err
+	}
+
+	idleStart := heartbeat.LastHeartbeat
+	if workLog.StartTime.After(idleStart) {
+		idleStart = workLog.StartTime
+	}
+	for _, pause := range pauses {
+		if pause.EndTime.IsZero() {
+			return nil
+		}
+		if pause.EndTime.After(idleStart) {
+			idleStart = pause.EndTime
+		}
+	}
+	if idleStart.Add(time.Duration(heartbeat.IdleThreshold) * time.Second).After(now) {
+		return nil
+	}
+
+	pause := util.Pause{
+		StartTime: idleStart,
+		WorkLogId: workLog.Id,
+	}
+	_, err = s.pauseDb.AddPause(ctx, logger, pause)
+	if errors.Is(err, util.ErrWorkLogPaused) {
+		// The user paused the work log since it was checked.
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	// The backdated pause takes focus time out of periods that may have been counted already.
+	err = s.streakDb.ResetStreaks(ctx, logger, heartbeat.UserId, idleStart)
+	if err != nil {
+		return err
+	}
+
+	logger.LogAttrs(ctx, slog.LevelInfo, "Paused idle work log", slog.Uint64("userId", heartbeat.UserId), slog.Uint64("workLogId", workLog.Id))
+	return nil
+}
+
+// setHeartbeat saves the heartbeat with its idle time updated.
+func (s *HeartbeatService) setHeartbeat(ctx context.Context, logger *slog.Logger, heartbeat util.Heartbeat) (util.Heartbeat, error) {
+	heartbeat.IdleTime = time.Time{}
+	if !heartbeat.LastHeartbeat.IsZero() {
+		heartbeat.IdleTime = heartbeat.LastHeartbeat.Add(time.Duration(heartbeat.IdleThreshold) * time.Second)
+	}
+
+	err := s.heartbeatDb.SetHeartbeat(ctx, logger, heartbeat)
+	if err != nil {
+		return util.Heartbeat{}, err
+	}
+
+	return heartbeat, nil
+}
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/NerdBow/Grinders-API/internal/util"
+)
+
+// GetLeaderboard ranks the members of the group by focused time and then completed tasks within the current period.
+// The period is a day, a week starting on Monday or a month in the time zone of the user and defaults to a week.
+// Members that opted out of leaderboards are left out. Work logs that overlap the period only count the time inside it
+// and running work logs count up to now, like the progress of time goals.
+// Every member can see the leaderboard, unlike member time, because members can opt out of it.
+func (s *GroupService) GetLeaderboard(ctx context.Context, logger *slog.Logger, userId uint64, groupId uint64, period string) (util.Leaderboard, error) {
+	switch period {
+	case "":
+		period = util.PERIOD_WEEK
+	case util.PERIOD_DAY, util.PERIOD_WEEK, util.PERIOD_MONTH:
+	default:
+		return util.Leaderboard{}, util.ErrInvalidPeriod
+	}
+
+	_, err := s.authorize(ctx, logger, userId, groupId, PERMISSION_VIEW)
+	if err != nil {
+		return util.Leaderboard{}, err
+	}
+
+	loc, err := userLocation(ctx, logger, s.settingsDb, userId)
+	if err != nil {
+		return util.Leaderboard{}, err
+	}
+
+	now := time.Now().In(loc)
+	periodStart, periodEnd := periodBounds(period, now)
+	entries, err := s.leaderboardDb.GetLeaderboard(ctx, logger, groupId, periodStart.UTC(), now.UTC())
+	if err != nil {
+		return util.Leaderboard{}, err
+	}
+
+	return util.Leaderboard{
+		GroupId:     groupId,
+		Period:      period,
+		PeriodStart: periodStart,
+		PeriodEnd:   periodEnd,
+		Entries:     entries,
+	}, nil
+}
+
+// GetLeaderboardPrivacy returns if the user opted out of leaderboards.
+func (s *GroupService) GetLeaderboardPrivacy(ctx context.Context, logger *slog.Logger, userId uint64) (util.LeaderboardPrivacy, error) {
+	if userId < 1 {
+		return util.LeaderboardPrivacy{}, util.ErrInvalidUserId
+	}
+
+	optedOut, err := s.leaderboardDb.GetLeaderboardOptOut(ctx, logger, userId)
+	if err != nil {
+		return util.LeaderboardPrivacy{}, err
+	}
+	return util.LeaderboardPrivacy{OptedOut: optedOut}, nil
+}
+
+// SetLeaderboardPrivacy opts the user out of the leaderboards of every group the user is in, or back in.
+func (s *GroupService) SetLeaderboardPrivacy(ctx context.Context, logger *slog.Logger, userId uint64, privacy util.LeaderboardPrivacy) (util.LeaderboardPrivacy, error) {
+	if userId < 1 {
+		return util.LeaderboardPrivacy{}, util.ErrInvalidUserId
+	}
+
+	err := s.leaderboardDb.SetLeaderboardOptOut(ctx, logger, userId, privacy.OptedOut)
+	if err != nil {
+		return util.LeaderboardPrivacy{}, err
+	}
+	return privacy, nil
+}
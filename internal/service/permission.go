@@ -0,0 +1,60 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/NerdBow/Grinders-API/internal/util"
+)
+
+// Permission is an action within a group that depends on the role of the member taking it.
+type Permission uint16
+
+const (
+	PERMISSION_VIEW            Permission = 1 << iota // See the group, its members and its shared categories
+	PERMISSION_VIEW_TIME                              // See the total focused time of members, which includes their private categories
+	PERMISSION_INVITE                                 // Add members and manage invites
+	PERMISSION_REMOVE_MEMBERS                         // Remove members ranked below the remover
+	PERMISSION_EDIT_CATEGORIES                        // Create, rename and delete shared categories
+	PERMISSION_MANAGE_ROLES                           // Promote and demote members
+	PERMISSION_MANAGE_GROUP                           // Rename, delete and transfer the group
+)
+
+// Only admins and the owner can see member time, because it is summed over all of a member's work logs and not only those in the group.
+// They are also the only ones who can change the shared categories, since every member of the group uses them.
+var rolePermissions = map[util.GroupRole]Permission{
+	util.ROLE_VIEWER: PERMISSION_VIEW,
+	util.ROLE_MEMBER: PERMISSION_VIEW,
+	util.ROLE_ADMIN:  PERMISSION_VIEW | PERMISSION_VIEW_TIME | PERMISSION_EDIT_CATEGORIES | PERMISSION_INVITE | PERMISSION_REMOVE_MEMBERS,
+	util.ROLE_OWNER:  PERMISSION_VIEW | PERMISSION_VIEW_TIME | PERMISSION_EDIT_CATEGORIES | PERMISSION_INVITE | PERMISSION_REMOVE_MEMBERS | PERMISSION_MANAGE_ROLES | PERMISSION_MANAGE_GROUP,
+}
+
+// can reports if the role has the permission.
+func can(role util.GroupRole, permission Permission) bool {
+	return rolePermissions[role]&permission == permission
+}
+
+// authorize returns the membership of the user in the group if the role of the user has the permission.
+// Users that are not members get ErrGroupNotFound so they can not tell if the group exists, members without the permission get ErrGroupPermission.
+func (s *GroupService) authorize(ctx context.Context, logger *slog.Logger, userId uint64, groupId uint64, permission Permission) (util.GroupMember, error) {
+	if userId < 1 {
+		return util.GroupMember{}, util.ErrInvalidUserId
+	}
+	if groupId < 1 {
+		return util.GroupMember{}, util.ErrInvalidGroupId
+	}
+
+	member, err := s.memberDb.GetGroupMember(ctx, logger, groupId, userId)
+	if errors.Is(err, util.ErrMemberNotFound) {
+		return util.GroupMember{}, util.ErrGroupNotFound
+	}
+	if err != nil {
+		return util.GroupMember{}, err
+	}
+
+	if !can(member.Role, permission) {
+		return util.GroupMember{}, util.ErrGroupPermission
+	}
+	return member, nil
+}
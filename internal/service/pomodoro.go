@@ -0,0 +1,419 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/NerdBow/Grinders-API/internal/database"
+	"github.com/NerdBow/Grinders-API/internal/util"
+)
+
+const (
+	POMODORO_TICK_INTERVAL = 10 * time.Second // How often the background ticker advances pomodoros whose phase has ended
+	POMODORO_ATTEMPTS      = 3                // How often a pomodoro is read again when another request advanced it at the same time
+	MIN_POMODORO_LENGTH    = 60               // In seconds
+	MAX_POMODORO_LENGTH    = 24 * 60 * 60     // In seconds
+)
+
+// DEFAULT_POMODORO_SETTINGS are used for users who have not set their own.
+var DEFAULT_POMODORO_SETTINGS = util.PomodoroSettings{
+	FocusLength:           25 * 60,
+	ShortBreakLength:      5 * 60,
+	LongBreakLength:       15 * 60,
+	CyclesBeforeLongBreak: 4,
+}
+
+// PomodoroService runs the Pomodoro cycles of users.
+// A focus phase runs a work log, when it ends the work log is stopped and a break is started after it.
+// When the break ends the cycle is idle until the next focus phase is started.
+//
+// Phases are advanced lazily whenever a pomodoro is read and by AdvanceDuePomodoros in the background,
+// so every device sees the same phase.
+// A pomodoro is only saved if it is still in the phase it was read in, so a phase is never ended twice.
+type PomodoroService struct {
+	pomodoroDb database.PomodorosDB
+	breakDb    database.BreaksDB
+	workLogs   WorkLogService
+}
+
+func NewPomodoroService(pomodoroDb database.PomodorosDB, breakDb database.BreaksDB, workLogDb database.WorkLogsDB, pauseDb database.PausesDB, taskDb database.TasksDB, streakDb database.StreaksDB) PomodoroService {
+	return PomodoroService{
+		pomodoroDb: pomodoroDb,
+		breakDb:    breakDb,
+		workLogs:   NewWorkLogService(workLogDb, pauseDb, taskDb, streakDb),
+	}
+}
+
+// GetSettings returns the Pomodoro settings of the user, or the defaults if they have none.
+func (s *PomodoroService) GetSettings(ctx context.Context, logger *slog.Logger, userId uint64) (util.PomodoroSettings, error) {
+	if userId < 1 {
+		return util.PomodoroSettings{}, util.ErrInvalidUserId
+	}
+
+	settings, err := s.pomodoroDb.GetPomodoroSettings(ctx, logger, userId)
+	if err != nil {
+		return util.PomodoroSettings{}, err
+	}
+	if settings.FocusLength == 0 {
+		settings = DEFAULT_POMODORO_SETTINGS
+		settings.UserId = userId
+	}
+
+	return settings, nil
+}
+
+// SetSettings replaces the Pomodoro settings of the user.
+// A running focus phase uses the new focus length right away, a running break keeps its length.
+func (s *PomodoroService) SetSettings(ctx context.Context, logger *slog.Logger, userId uint64, settings util.PomodoroSettings) (util.PomodoroSettings, error) {
+	if userId < 1 {
+		return util.PomodoroSettings{}, util.ErrInvalidUserId
+	}
+	for _, length := range []int64{settings.FocusLength, settings.ShortBreakLength, settings.LongBreakLength} {
+		if length < MIN_POMODORO_LENGTH || length > MAX_POMODORO_LENGTH {
+			return util.PomodoroSettings{}, util.ErrInvalidPomodoro
+		}
+	}
+	if settings.CyclesBeforeLongBreak < 1 {
+		return util.PomodoroSettings{}, util.ErrInvalidPomodoro
+	}
+
+	settings.UserId = userId
+	err := s.pomodoroDb.SetPomodoroSettings(ctx, logger, settings)
+	if err != nil {
+		return util.PomodoroSettings{}, err
+	}
+
+	return settings, nil
+}
+
+// GetPomodoro returns the current phase of the user's Pomodoro cycle and the time remaining in it.
+func (s *PomodoroService) GetPomodoro(ctx context.Context, logger *slog.Logger, userId uint64) (util.Pomodoro, error) {
+	if userId < 1 {
+		return util.Pomodoro{}, util.ErrInvalidUserId
+	}
+
+	now := time.Now().UTC()
+	pomodoro, err := s.current(ctx, logger, userId, now)
+	if err != nil {
+		return util.Pomodoro{}, err
+	}
+
+	return withRemaining(pomodoro, now), nil
+}
+
+// StartFocus starts the next focus phase of the user's Pomodoro cycle with a new work log on the task.
+// A running break is ended early. The task and objective of the last focus phase are used if they are not given.
+func (s *PomodoroService) StartFocus(ctx context.Context, logger *slog.Logger, userId uint64, taskId uint64, objective string) (util.Pomodoro, error) {
+	if userId < 1 {
+		return util.Pomodoro{}, util.ErrInvalidUserId
+	}
+
+	now := time.Now().UTC()
+	pomodoro, err := s.current(ctx, logger, userId, now)
+	isNew := errors.Is(err, util.ErrPomodoroNotFound)
+	if isNew {
+		pomodoro = util.Pomodoro{Phase: util.POMODORO_IDLE, UserId: userId}
+	} else if err != nil {
+		return util.Pomodoro{}, err
+	}
+	previous := pomodoro
+
+	switch pomodoro.Phase {
+	case util.POMODORO_FOCUS:
+		return util.Pomodoro{}, util.ErrPomodoroFocusing
+	case util.POMODORO_SHORT_BREAK, util.POMODORO_LONG_BREAK:
+		err = s.endBreak(ctx, logger, userId, pomodoro.BreakId, now)
+		if err != nil {
+			return util.Pomodoro{}, err
+		}
+	}
+
+	if taskId == 0 {
+		taskId = pomodoro.TaskId
+	}
+	if objective == "" {
+		objective = pomodoro.Objective
+	}
+	if objective == "" {
+		return util.Pomodoro{}, fmt.Errorf("%w for an objective", util.ErrEmptyString)
+	}
+
+	workLog, err := s.workLogs.StartWorkLog(ctx, logger, userId, taskId, objective, false)
+	if err != nil {
+		return util.Pomodoro{}, err
+	}
+
+	settings, err := s.GetSettings(ctx, logger, userId)
+	if err != nil {
+		return util.Pomodoro{}, err
+	}
+
+	pomodoro.Phase = util.POMODORO_FOCUS
+	pomodoro.TaskId = taskId
+	pomodoro.Objective = objective
+	pomodoro.PhaseStart = workLog.StartTime
+	pomodoro.PhaseEnd = workLog.StartTime.Add(time.Duration(settings.FocusLength) * time.Second)
+	pomodoro.WorkLogId = workLog.Id
+	pomodoro.BreakId = 0
+
+	if isNew {
+		err = s.pomodoroDb.AddPomodoro(ctx, logger, pomodoro)
+	} else {
+		err = s.pomodoroDb.UpdatePomodoro(ctx, logger, pomodoro, previous)
+	}
+	if err != nil {
+		return util.Pomodoro{}, err
+	}
+
+	return withRemaining(pomodoro, workLog.StartTime), nil
+}
+
+// StopPomodoro ends the user's Pomodoro cycle, stopping the running work log or break early.
+func (s *PomodoroService) StopPomodoro(ctx context.Context, logger *slog.Logger, userId uint64) error {
+	if userId < 1 {
+		return util.ErrInvalidUserId
+	}
+
+	now := time.Now().UTC()
+	pomodoro, err := s.current(ctx, logger, userId, now)
+	if err != nil {
+		return err
+	}
+
+	switch pomodoro.Phase {
+	case util.POMODORO_FOCUS:
+		workLog, err := s.workLogs.getRunningWorkLog(ctx, logger, userId, pomodoro.WorkLogId)
+		if err == nil {
+			_, err = s.workLogs.stop(ctx, logger, workLog, "", now)
+		}
+		if err != nil && !errors.Is(err, util.ErrWorkLogStopped) && !errors.Is(err, util.ErrWorkLogNotFound) {
+			return err
+		}
+	case util.POMODORO_SHORT_BREAK, util.POMODORO_LONG_BREAK:
+		err = s.endBreak(ctx, logger, userId, pomodoro.BreakId, now)
+		if err != nil {
+			return err
+		}
+	}
+
+	return s.pomodoroDb.DeletePomodoro(ctx, logger, pomodoro)
+}
+
+// AdvanceDuePomodoros moves every pomodoro whose phase has ended on to its next phase.
+// Failures are logged and do not stop the other pomodoros from advancing.
+// Pomodoros that a request advanced at the same time are left to that request.
+func (s *PomodoroService) AdvanceDuePomodoros(ctx context.Context, logger *slog.Logger) {
+	now := time.Now().UTC()
+	pomodoros, err := s.pomodoroDb.GetDuePomodoros(ctx, logger, now)
+	if err != nil {
+		return
+	}
+
+	for _, pomodoro := range pomodoros {
+		_, err = s.advance(ctx, logger, pomodoro, now)
+		if errors.Is(err, util.ErrPomodoroChanged) {
+			logger.LogAttrs(ctx, slog.LevelDebug, "AdvanceDuePomodoros", slog.Uint64("userId", pomodoro.UserId), slog.String("err", err.Error()))
+		} else if err != nil {
+			logger.LogAttrs(ctx, slog.LevelWarn, "AdvanceDuePomodoros", slog.Uint64("userId", pomodoro.UserId), slog.String("err", err.Error()))
+		}
+	}
+}
+
+// current reads the user's pomodoro and advances it to now.
+// It is read again if another request advanced it at the same time.
+func (s *PomodoroService) current(ctx context.Context, logger *slog.Logger, userId uint64, now time.Time) (util.Pomodoro, error) {
+	for attempt := 1; ; attempt++ {
+		pomodoro, err := s.pomodoroDb.GetPomodoro(ctx, logger, userId)
+		if err != nil {
+			return util.Pomodoro{}, err
+		}
+
+		pomodoro, err = s.advance(ctx, logger, pomodoro, now)
+		if errors.Is(err, util.ErrPomodoroChanged) && attempt < POMODORO_ATTEMPTS {
+			continue
+		}
+		return pomodoro, err
+	}
+}
+
+// advance catches the pomodoro up to now and saves it if its phase changed.
+// The focus phase is extended by the pauses of its work log.
+// A work log or break that was ended by hand ends its phase at the same time.
+// If the pomodoro was advanced by another request since it was read, then ErrPomodoroChanged is returned.
+func (s *PomodoroService) advance(ctx context.Context, logger *slog.Logger, pomodoro util.Pomodoro, now time.Time) (util.Pomodoro, error) {
+	settings, err := s.GetSettings(ctx, logger, pomodoro.UserId)
+	if err != nil {
+		return util.Pomodoro{}, err
+	}
+
+	original := pomodoro
+	for {
+		switch pomodoro.Phase {
+		case util.POMODORO_FOCUS:
+			pomodoro, err = s.advanceFocus(ctx, logger, pomodoro, settings, now)
+		case util.POMODORO_SHORT_BREAK, util.POMODORO_LONG_BREAK:
+			pomodoro, err = s.advanceBreak(ctx, logger, pomodoro, now)
+		}
+		if err != nil {
+			return util.Pomodoro{}, err
+		}
+		if pomodoro.Phase == util.POMODORO_IDLE || pomodoro.PhaseEnd.After(now) {
+			break
+		}
+	}
+
+	if pomodoro != original {
+		err = s.pomodoroDb.UpdatePomodoro(ctx, logger, pomodoro, original)
+		if err != nil {
+			return util.Pomodoro{}, err
+		}
+	}
+
+	return pomodoro, nil
+}
+
+// advanceFocus stops the work log and starts a break if the focus phase ended by now.
+// Otherwise the end of the phase is updated for the pauses taken so far.
+// A work log that no longer exists was stopped and merged into another, so the pomodoro goes idle without a break.
+func (s *PomodoroService) advanceFocus(ctx context.Context, logger *slog.Logger, pomodoro util.Pomodoro, settings util.PomodoroSettings, now time.Time) (util.Pomodoro, error) {
+	workLog, err := s.workLogs.workLogDb.GetWorkLog(ctx, logger, pomodoro.WorkLogId, pomodoro.UserId)
+	if errors.Is(err, util.ErrWorkLogNotFound) {
+		phaseEnd := pomodoro.PhaseEnd
+		if phaseEnd.After(now) {
+			phaseEnd = now
+		}
+
+		pomodoro.Cycle++
+		pomodoro.Phase = util.POMODORO_IDLE
+		pomodoro.PhaseStart = phaseEnd
+		pomodoro.PhaseEnd = time.Time{}
+		return pomodoro, nil
+	}
+	if err != nil {
+		return util.Pomodoro{}, err
+	}
+
+	phaseEnd := workLog.EndTime
+	if !workLog.IsComplete {
+		pauses, err := s.workLogs.pauseDb.GetPauses(ctx, logger, workLog.Id)
+		if err != nil {
+			return util.Pomodoro{}, err
+		}
+
+		paused := newTimeline(workLog, pauses, now).PausedDuration
+		phaseEnd = pomodoro.PhaseStart.Add(time.Duration(settings.FocusLength+paused) * time.Second)
+		if phaseEnd.After(now) {
+			pomodoro.PhaseEnd = phaseEnd
+			return pomodoro, nil
+		}
+
+		_, err = s.workLogs.stop(ctx, logger, workLog, "", phaseEnd)
+		if errors.Is(err, util.ErrWorkLogNotFound) {
+			// The work log was stopped by another request since it was read.
+			return util.Pomodoro{}, util.ErrPomodoroChanged
+		}
+		if err != nil {
+			return util.Pomodoro{}, err
+		}
+	}
+
+	pomodoro.Cycle++
+	breakLength := settings.ShortBreakLength
+	pomodoro.Phase = util.POMODORO_SHORT_BREAK
+	if pomodoro.Cycle%settings.CyclesBeforeLongBreak == 0 {
+		breakLength = settings.LongBreakLength
+		pomodoro.Phase = util.POMODORO_LONG_BREAK
+	}
+
+	brk := util.Break{
+		StartTime: phaseEnd,
+		WorkLogId: workLog.Id,
+	}
+	pomodoro.BreakId, err = s.breakDb.AddBreak(ctx, logger, brk)
+	if errors.Is(err, util.ErrBreakRunning) {
+		// Another request ended the focus phase first, or the user is already taking a break, so it is used for the phase.
+		running, err := s.breakDb.GetRunningBreak(ctx, logger, pomodoro.UserId)
+		if errors.Is(err, util.ErrBreakNotFound) {
+			return util.Pomodoro{}, util.ErrPomodoroChanged
+		}
+		if err != nil {
+			return util.Pomodoro{}, err
+		}
+		pomodoro.BreakId = running.Id
+	} else if errors.Is(err, util.ErrWorkLogNotFound) {
+		return util.Pomodoro{}, util.ErrPomodoroChanged
+	} else if err != nil {
+		return util.Pomodoro{}, err
+	}
+
+	pomodoro.PhaseStart = phaseEnd
+	pomodoro.PhaseEnd = phaseEnd.Add(time.Duration(breakLength) * time.Second)
+	logger.LogAttrs(ctx, slog.LevelDebug, "Pomodoro focus ended", slog.Uint64("userId", pomodoro.UserId), slog.Uint64("cycle", uint64(pomodoro.Cycle)))
+
+	return pomodoro, nil
+}
+
+// advanceBreak ends the break and makes the pomodoro idle if the break phase ended by now.
+// A break that no longer exists ends the phase.
+func (s *PomodoroService) advanceBreak(ctx context.Context, logger *slog.Logger, pomodoro util.Pomodoro, now time.Time) (util.Pomodoro, error) {
+	brk, err := s.breakDb.GetBreak(ctx, logger, pomodoro.BreakId, pomodoro.UserId)
+	phaseEnd := pomodoro.PhaseEnd
+	if errors.Is(err, util.ErrBreakNotFound) {
+		if phaseEnd.After(now) {
+			phaseEnd = now
+		}
+	} else if err != nil {
+		return util.Pomodoro{}, err
+	} else if !brk.EndTime.IsZero() {
+		phaseEnd = brk.EndTime
+	}
+	if phaseEnd.After(now) {
+		return pomodoro, nil
+	}
+
+	err = s.endBreak(ctx, logger, pomodoro.UserId, brk.Id, phaseEnd)
+	if err != nil {
+		return util.Pomodoro{}, err
+	}
+
+	pomodoro.Phase = util.POMODORO_IDLE
+	pomodoro.PhaseStart = phaseEnd
+	pomodoro.PhaseEnd = time.Time{}
+
+	return pomodoro, nil
+}
+
+// endBreak ends the break at endTime if it is still running.
+// A break that no longer exists or was ended at the same time is left as it is.
+func (s *PomodoroService) endBreak(ctx context.Context, logger *slog.Logger, userId uint64, breakId uint64, endTime time.Time) error {
+	brk, err := s.breakDb.GetBreak(ctx, logger, breakId, userId)
+	if errors.Is(err, util.ErrBreakNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !brk.EndTime.IsZero() {
+		return nil
+	}
+
+	brk.EndTime = endTime
+	brk.Duration = int64(brk.EndTime.Sub(brk.StartTime) / time.Second)
+	err = s.breakDb.EndBreak(ctx, logger, brk)
+	if errors.Is(err, util.ErrBreakNotFound) {
+		return nil
+	}
+	return err
+}
+
+// withRemaining sets the seconds remaining in the phase of the pomodoro at now.
+func withRemaining(pomodoro util.Pomodoro, now time.Time) util.Pomodoro {
+	pomodoro.Remaining = 0
+	if pomodoro.PhaseEnd.After(now) {
+		pomodoro.Remaining = int64(pomodoro.PhaseEnd.Sub(now) / time.Second)
+	}
+	return pomodoro
+}
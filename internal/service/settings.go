@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/NerdBow/Grinders-API/internal/database"
+	"github.com/NerdBow/Grinders-API/internal/util"
+)
+
+type UserSettingsService struct {
+	settingsDb database.UserSettingsDB
+	streakDb   database.StreaksDB
+}
+
+func NewUserSettingsService(settingsDb database.UserSettingsDB, streakDb database.StreaksDB) UserSettingsService {
+	return UserSettingsService{
+		settingsDb: settingsDb,
+		streakDb:   streakDb,
+	}
+}
+
+// GetSettings returns the settings of the user.
+func (s *UserSettingsService) GetSettings(ctx context.Context, logger *slog.Logger, userId uint64) (util.UserSettings, error) {
+	if userId < 1 {
+		return util.UserSettings{}, util.ErrInvalidUserId
+	}
+
+	return s.settingsDb.GetUserSettings(ctx, logger, userId)
+}
+
+// SetSettings replaces the settings of the user.
+// Changing the time zone moves the bounds of every period, so all stored streaks of the user are counted again.
+func (s *UserSettingsService) SetSettings(ctx context.Context, logger *slog.Logger, userId uint64, settings util.UserSettings) (util.UserSettings, error) {
+	if userId < 1 {
+		return util.UserSettings{}, util.ErrInvalidUserId
+	}
+	if _, err := time.LoadLocation(settings.TimeZone); err != nil {
+		return util.UserSettings{}, util.ErrInvalidTimeZone
+	}
+
+	current, err := s.settingsDb.GetUserSettings(ctx, logger, userId)
+	if err != nil {
+		return util.UserSettings{}, err
+	}
+
+	settings.UserId = userId
+	err = s.settingsDb.SetUserSettings(ctx, logger, settings)
+	if err != nil {
+		return util.UserSettings{}, err
+	}
+
+	if settings.TimeZone != current.TimeZone {
+		err = s.streakDb.ResetStreaks(ctx, logger, userId, time.Time{})
+		if err != nil {
+			return util.UserSettings{}, err
+		}
+	}
+
+	return settings, nil
+}
+
+// userLocation returns the time zone of the user, UTC if they have not set one.
+func userLocation(ctx context.Context, logger *slog.Logger, settingsDb database.UserSettingsDB, userId uint64) (*time.Location, error) {
+	settings, err := settingsDb.GetUserSettings(ctx, logger, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	loc, err := time.LoadLocation(settings.TimeZone)
+	if err != nil {
+		logger.LogAttrs(ctx, slog.LevelWarn, "userLocation", slog.Uint64("userId", userId), slog.String("err", err.Error()))
+		return time.UTC, nil
+	}
+	return loc, nil
+}
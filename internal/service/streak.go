@@ -0,0 +1,294 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/NerdBow/Grinders-API/internal/database"
+	"github.com/NerdBow/Grinders-API/internal/util"
+)
+
+// StreakService counts the consecutive periods that goals were met in and the consecutive days that focus time was logged in.
+// Streaks are stored with the start of the first period that has not been counted yet, so only the periods since then are counted on a request.
+// Changes to work logs and rest days in counted periods reset the stored streaks, which are then counted again from the start.
+type StreakService struct {
+	streakDb   database.StreaksDB
+	restDayDb  database.RestDaysDB
+	goalDb     database.GoalsDB
+	workLogDb  database.WorkLogsDB
+	pauseDb    database.PausesDB
+	settingsDb database.UserSettingsDB
+	evaluators map[util.GoalType]goalEvaluator
+}
+
+func NewStreakService(streakDb database.StreaksDB, restDayDb database.RestDaysDB, goalDb database.GoalsDB, workLogDb database.WorkLogsDB, pauseDb database.PausesDB, settingsDb database.UserSettingsDB) StreakService {
+	return StreakService{
+		streakDb:   streakDb,
+		restDayDb:  restDayDb,
+		goalDb:     goalDb,
+		workLogDb:  workLogDb,
+		pauseDb:    pauseDb,
+		settingsDb: settingsDb,
+		evaluators: newGoalEvaluators(goalDb, pauseDb),
+	}
+}
+
+// GetStreaks returns the activity streak of the user followed by the streaks of the user's goals.
+// The periods are days, weeks starting on Monday or months in the time zone of the user.
+func (s *StreakService) GetStreaks(ctx context.Context, logger *slog.Logger, userId uint64) ([]util.Streak, error) {
+	if userId < 1 {
+		return nil, util.ErrInvalidUserId
+	}
+
+	loc, err := userLocation(ctx, logger, s.settingsDb, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	stored, err := s.streakDb.GetStreaks(ctx, logger, userId)
+	if err != nil {
+		return nil, err
+	}
+	storedStreaks := make(map[uint64]util.Streak, len(stored))
+	for _, streak := range stored {
+		storedStreaks[streak.GoalId] = streak
+	}
+
+	goals, err := s.goalDb.GetUserGoals(ctx, logger, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	restDays, err := s.restDayDb.GetRestDays(ctx, logger, userId, "", "")
+	if err != nil {
+		return nil, err
+	}
+	isRestDay := make(map[string]bool, len(restDays))
+	for _, date := range restDays {
+		isRestDay[date] = true
+	}
+
+	counter := streakCounter{
+		now:       time.Now().UTC(),
+		loc:       loc,
+		isRestDay: isRestDay,
+	}
+
+	streaks := make([]util.Streak, 0, len(goals)+1)
+
+	streak, err := s.countActivity(ctx, logger, &counter, storedStreaks[0], userId)
+	if err != nil {
+		return nil, err
+	}
+	streaks = append(streaks, streak)
+
+	for _, goal := range goals {
+		streak, err := s.countGoal(ctx, logger, &counter, storedStreaks[goal.Id], userId, goal)
+		if err != nil {
+			return nil, err
+		}
+		streaks = append(streaks, streak)
+	}
+
+	return streaks, nil
+}
+
+// GetRestDays returns the rest days of the user from the date from up to and including the date to.
+// Empty dates are not bounded.
+func (s *StreakService) GetRestDays(ctx context.Context, logger *slog.Logger, userId uint64, from string, to string) ([]string, error) {
+	if userId < 1 {
+		return nil, util.ErrInvalidUserId
+	}
+
+	return s.restDayDb.GetRestDays(ctx, logger, userId, from, to)
+}
+
+// AddRestDay marks the date, formatted as 2006-01-02 in the time zone of the user, as a rest day of the user.
+func (s *StreakService) AddRestDay(ctx context.Context, logger *slog.Logger, userId uint64, date string) error {
+	day, err := s.restDay(ctx, logger, userId, date)
+	if err != nil {
+		return err
+	}
+
+	err = s.restDayDb.AddRestDay(ctx, logger, userId, date)
+	if err != nil {
+		return err
+	}
+
+	return s.streakDb.ResetStreaks(ctx, logger, userId, day)
+}
+
+// DeleteRestDay removes the rest day of the user on the date.
+func (s *StreakService) DeleteRestDay(ctx context.Context, logger *slog.Logger, userId uint64, date string) error {
+	day, err := s.restDay(ctx, logger, userId, date)
+	if err != nil {
+		return err
+	}
+
+	err = s.restDayDb.DeleteRestDay(ctx, logger, userId, date)
+	if err != nil {
+		return err
+	}
+
+	return s.streakDb.ResetStreaks(ctx, logger, userId, day)
+}
+
+// restDay returns the start of the date in the time zone of the user.
+func (s *StreakService) restDay(ctx context.Context, logger *slog.Logger, userId uint64, date string) (time.Time, error) {
+	if userId < 1 {
+		return time.Time{}, util.ErrInvalidUserId
+	}
+
+	loc, err := userLocation(ctx, logger, s.settingsDb, userId)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	day, err := time.ParseInLocation(DATE_FORMAT, date, loc)
+	if err != nil {
+		return time.Time{}, util.ErrInvalidRestDay
+	}
+	return day.UTC(), nil
+}
+
+// streakCounter holds what is shared between counting the streaks of a user.
+type streakCounter struct {
+	now       time.Time
+	loc       *time.Location
+	isRestDay map[string]bool
+}
+
+// countActivity counts the days the user logged any focus time in.
+// The work logs since the streak was last counted are read at once and their focused time is split into days.
+func (s *StreakService) countActivity(ctx context.Context, logger *slog.Logger, counter *streakCounter, streak util.Streak, userId uint64) (util.Streak, error) {
+	streak.UserId = userId
+	streak.Period = util.PERIOD_DAY
+
+	workLogs, err := s.workLogDb.GetWorkLogsBetween(ctx, logger, userId, streak.CheckedUntil, counter.now)
+	if err != nil {
+		return util.Streak{}, err
+	}
+
+	focused := make(map[time.Time]int64)
+	for _, workLog := range workLogs {
+		pauses, err := s.pauseDb.GetPauses(ctx, logger, workLog.Id)
+		if err != nil {
+			return util.Streak{}, err
+		}
+
+		end := workLog.EndTime
+		if !workLog.IsComplete {
+			end = counter.now
+		}
+		dayStart, dayEnd := periodBounds(util.PERIOD_DAY, workLog.StartTime.In(counter.loc))
+		for dayStart.Before(end) {
+			from, to := workLog.StartTime, end
+			if from.Before(dayStart) {
+				from = dayStart
+			}
+			if to.After(dayEnd) {
+				to = dayEnd
+			}
+			if to.After(from) {
+				focused[dayStart.UTC()] += focusedDuration(from, to, pauses)
+			}
+			dayStart, dayEnd = periodBounds(util.PERIOD_DAY, dayEnd)
+		}
+	}
+
+	start := counter.now
+	if len(workLogs) > 0 {
+		start = workLogs[0].StartTime
+	}
+
+	return s.count(ctx, logger, counter, streak, start, func(from time.Time, to time.Time, now time.Time) (bool, error) {
+		return focused[from.UTC()] > 0, nil
+	})
+}
+
+// countGoal counts the periods the goal was met in, evaluating each period since the streak was last counted.
+func (s *StreakService) countGoal(ctx context.Context, logger *slog.Logger, counter *streakCounter, streak util.Streak, userId uint64, goal util.Goal) (util.Streak, error) {
+	evaluator, ok := s.evaluators[goal.Type]
+	if !ok {
+		return util.Streak{}, util.ErrInvalidGoal
+	}
+
+	streak.UserId = userId
+	streak.GoalId = goal.Id
+	streak.Period = evaluator.period()
+
+	start := counter.now
+	if streak.CheckedUntil.IsZero() {
+		categoryStart, err := s.goalDb.GetCategoryStart(ctx, logger, userId, goal.CategoryId)
+		if err != nil {
+			return util.Streak{}, err
+		}
+		if !categoryStart.IsZero() {
+			start = categoryStart
+		}
+	}
+
+	return s.count(ctx, logger, counter, streak, start, func(from time.Time, to time.Time, now time.Time) (bool, error) {
+		progress, _, err := evaluator.evaluate(ctx, logger, userId, goal, from.UTC(), to.UTC(), now)
+		return progress >= goal.Value, err
+	})
+}
+
+// count adds the periods since the streak was last counted, or since start if it never was, up to the period containing now.
+// met reports if the period from to was met as of now, which is not after to.
+// Complete periods are stored on the streak, the current period only adds to the returned streak once it is met.
+func (s *StreakService) count(ctx context.Context, logger *slog.Logger, counter *streakCounter, streak util.Streak, start time.Time, met func(from time.Time, to time.Time, now time.Time) (bool, error)) (util.Streak, error) {
+	currentStart, currentEnd := periodBounds(streak.Period, counter.now.In(counter.loc))
+
+	if !streak.CheckedUntil.IsZero() {
+		start = streak.CheckedUntil
+	}
+	periodStart, periodEnd := periodBounds(streak.Period, start.In(counter.loc))
+
+	changed := false
+	for periodStart.Before(currentStart) {
+		isMet, err := met(periodStart, periodEnd, periodEnd.UTC())
+		if err != nil {
+			return util.Streak{}, err
+		}
+
+		if isMet {
+			streak.Current++
+			streak.Longest = max(streak.Longest, streak.Current)
+		} else if !counter.allRestDays(periodStart, periodEnd) {
+			streak.Current = 0
+		}
+		periodStart, periodEnd = periodBounds(streak.Period, periodEnd)
+		changed = true
+	}
+
+	if changed || streak.CheckedUntil.IsZero() {
+		streak.CheckedUntil = currentStart.UTC()
+		err := s.streakDb.SaveStreak(ctx, logger, streak)
+		if err != nil {
+			return util.Streak{}, err
+		}
+	}
+
+	isMet, err := met(currentStart, currentEnd, counter.now)
+	if err != nil {
+		return util.Streak{}, err
+	}
+	if isMet {
+		streak.Current++
+		streak.Longest = max(streak.Longest, streak.Current)
+	}
+
+	return streak, nil
+}
+
+// allRestDays reports if every day from start up to end is a rest day.
+func (c *streakCounter) allRestDays(start time.Time, end time.Time) bool {
+	for day := start; day.Before(end); day = day.AddDate(0, 0, 1) {
+		if !c.isRestDay[day.Format(DATE_FORMAT)] {
+			return false
+		}
+	}
+	return true
+}
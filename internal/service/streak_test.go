@@ -0,0 +1,171 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/NerdBow/Grinders-API/internal/util"
+)
+
+// fakeStreakDB keeps the streaks that were saved in memory.
+type fakeStreakDB struct {
+	saved []util.Streak
+}
+
+func (db *fakeStreakDB) GetStreaks(ctx context.Context, logger *slog.Logger, userId uint64) ([]util.Streak, error) {
+	return db.saved, nil
+}
+
+func (db *fakeStreakDB) SaveStreak(ctx context.Context, logger *slog.Logger, streak util.Streak) error {
+	db.saved = append(db.saved, streak)
+	return nil
+}
+
+func (db *fakeStreakDB) ResetStreaks(ctx context.Context, logger *slog.Logger, userId uint64, from time.Time) error {
+	return nil
+}
+
+func (db *fakeStreakDB) DeleteGoalStreak(ctx context.Context, logger *slog.Logger, goalId uint64) error {
+	return nil
+}
+
+func TestStreakCount(t *testing.T) {
+	now := march(10).Add(12 * time.Hour)
+
+	tests := []struct {
+		name          string
+		period        string
+		stored        util.Streak
+		start         time.Time
+		met           []time.Time
+		restDays      []string
+		want          util.Streak
+		wantSaved     *util.Streak
+		wantEvaluated []time.Time
+	}{
+		{
+			name:          "counts from the start",
+			period:        util.PERIOD_DAY,
+			start:         march(5).Add(9 * time.Hour),
+			met:           []time.Time{march(5), march(6), march(7), march(8), march(9)},
+			want:          util.Streak{Current: 5, Longest: 5, CheckedUntil: march(10)},
+			wantSaved:     &util.Streak{Current: 5, Longest: 5, CheckedUntil: march(10)},
+			wantEvaluated: []time.Time{march(5), march(6), march(7), march(8), march(9), march(10)},
+		},
+		{
+			name:          "current period counts once met but is not stored",
+			period:        util.PERIOD_DAY,
+			start:         march(8),
+			met:           []time.Time{march(8), march(9), march(10)},
+			want:          util.Streak{Current: 3, Longest: 3, CheckedUntil: march(10)},
+			wantSaved:     &util.Streak{Current: 2, Longest: 2, CheckedUntil: march(10)},
+			wantEvaluated: []time.Time{march(8), march(9), march(10)},
+		},
+		{
+			name:          "missed period resets the current streak",
+			period:        util.PERIOD_DAY,
+			start:         march(5),
+			met:           []time.Time{march(5), march(6), march(7), march(9)},
+			want:          util.Streak{Current: 1, Longest: 3, CheckedUntil: march(10)},
+			wantSaved:     &util.Streak{Current: 1, Longest: 3, CheckedUntil: march(10)},
+			wantEvaluated: []time.Time{march(5), march(6), march(7), march(8), march(9), march(10)},
+		},
+		{
+			name:          "rest day does not break the streak",
+			period:        util.PERIOD_DAY,
+			start:         march(5),
+			met:           []time.Time{march(5), march(6), march(7), march(9)},
+			restDays:      []string{"2026-03-08"},
+			want:          util.Streak{Current: 4, Longest: 4, CheckedUntil: march(10)},
+			wantSaved:     &util.Streak{Current: 4, Longest: 4, CheckedUntil: march(10)},
+			wantEvaluated: []time.Time{march(5), march(6), march(7), march(8), march(9), march(10)},
+		},
+		{
+			name:          "stored streak only counts the periods since it was checked",
+			period:        util.PERIOD_DAY,
+			stored:        util.Streak{Current: 3, Longest: 4, CheckedUntil: march(8)},
+			start:         march(1),
+			met:           []time.Time{march(8), march(9)},
+			want:          util.Streak{Current: 5, Longest: 5, CheckedUntil: march(10)},
+			wantSaved:     &util.Streak{Current: 5, Longest: 5, CheckedUntil: march(10)},
+			wantEvaluated: []time.Time{march(8), march(9), march(10)},
+		},
+		{
+			name:          "stored streak is not saved again within the same period",
+			period:        util.PERIOD_DAY,
+			stored:        util.Streak{Current: 2, Longest: 6, CheckedUntil: march(10)},
+			start:         march(1),
+			met:           []time.Time{march(10)},
+			want:          util.Streak{Current: 3, Longest: 6, CheckedUntil: march(10)},
+			wantEvaluated: []time.Time{march(10)},
+		},
+		{
+			name:          "weeks start on Monday",
+			period:        util.PERIOD_WEEK,
+			start:         march(4),
+			met:           []time.Time{march(2)},
+			want:          util.Streak{Current: 1, Longest: 1, CheckedUntil: march(9)},
+			wantSaved:     &util.Streak{Current: 1, Longest: 1, CheckedUntil: march(9)},
+			wantEvaluated: []time.Time{march(2), march(9)},
+		},
+		{
+			name:          "week of rest days does not break the streak",
+			period:        util.PERIOD_WEEK,
+			stored:        util.Streak{Current: 2, Longest: 2, CheckedUntil: march(2)},
+			start:         march(1),
+			restDays:      []string{"2026-03-02", "2026-03-03", "2026-03-04", "2026-03-05", "2026-03-06", "2026-03-07", "2026-03-08"},
+			want:          util.Streak{Current: 2, Longest: 2, CheckedUntil: march(9)},
+			wantSaved:     &util.Streak{Current: 2, Longest: 2, CheckedUntil: march(9)},
+			wantEvaluated: []time.Time{march(2), march(9)},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			streakDb := &fakeStreakDB{}
+			s := StreakService{streakDb: streakDb}
+
+			isRestDay := make(map[string]bool, len(test.restDays))
+			for _, date := range test.restDays {
+				isRestDay[date] = true
+			}
+			counter := streakCounter{now: now, loc: time.UTC, isRestDay: isRestDay}
+
+			stored := test.stored
+			stored.Period = test.period
+			evaluated := make([]time.Time, 0, len(test.wantEvaluated))
+			got, err := s.count(context.Background(), testLogger(), &counter, stored, test.start, func(from time.Time, to time.Time, now time.Time) (bool, error) {
+				evaluated = append(evaluated, from)
+				return slices.ContainsFunc(test.met, from.Equal), nil
+			})
+			if err != nil {
+				t.Fatalf("count returned %v", err)
+			}
+
+			if got.Current != test.want.Current || got.Longest != test.want.Longest || !got.CheckedUntil.Equal(test.want.CheckedUntil) {
+				t.Errorf("count = current %d, longest %d, checked until %v, want current %d, longest %d, checked until %v",
+					got.Current, got.Longest, got.CheckedUntil, test.want.Current, test.want.Longest, test.want.CheckedUntil)
+			}
+
+			if !slices.EqualFunc(evaluated, test.wantEvaluated, time.Time.Equal) {
+				t.Errorf("evaluated periods %v, want %v", evaluated, test.wantEvaluated)
+			}
+
+			switch {
+			case test.wantSaved == nil && len(streakDb.saved) > 0:
+				t.Errorf("saved %+v, want nothing saved", streakDb.saved)
+			case test.wantSaved != nil && len(streakDb.saved) != 1:
+				t.Errorf("saved %d streaks, want 1", len(streakDb.saved))
+			case test.wantSaved != nil:
+				saved := streakDb.saved[0]
+				if saved.Current != test.wantSaved.Current || saved.Longest != test.wantSaved.Longest || !saved.CheckedUntil.Equal(test.wantSaved.CheckedUntil) {
+					t.Errorf("saved current %d, longest %d, checked until %v, want current %d, longest %d, checked until %v",
+						saved.Current, saved.Longest, saved.CheckedUntil, test.wantSaved.Current, test.wantSaved.Longest, test.wantSaved.CheckedUntil)
+				}
+			}
+		})
+	}
+}
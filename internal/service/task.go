@@ -0,0 +1,227 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/NerdBow/Grinders-API/internal/database"
+	"github.com/NerdBow/Grinders-API/internal/util"
+)
+
+type TaskService struct {
+	taskDb     database.TasksDB
+	categoryDb database.CategoriesDB
+	streakDb   database.StreaksDB
+}
+
+func NewTaskService(taskDb database.TasksDB, categoryDb database.CategoriesDB, streakDb database.StreaksDB) TaskService {
+	return TaskService{
+		taskDb:     taskDb,
+		categoryDb: categoryDb,
+		streakDb:   streakDb,
+	}
+}
+
+// CreateTask creates a new task for the user from the name, deadline and category of the given task.
+// All other fields of the task are ignored.
+func (s *TaskService) CreateTask(ctx context.Context, logger *slog.Logger, userId uint64, task util.Task) (util.Task, error) {
+	if userId < 1 {
+		return util.Task{}, util.ErrInvalidUserId
+	}
+	if task.Name == "" {
+		return util.Task{}, fmt.Errorf("%w for a task name", util.ErrEmptyString)
+	}
+	if task.CategoryId < 1 {
+		return util.Task{}, util.ErrInvalidCategoryId
+	}
+
+	_, err := s.categoryDb.GetCategoryById(ctx, logger, task.CategoryId, userId)
+	if err != nil {
+		return util.Task{}, err
+	}
+
+	newTask := util.Task{
+		Name:         task.Name,
+		CreationTime: time.Now().UTC(),
+		DeadlineTime: task.DeadlineTime.UTC(),
+		CategoryId:   task.CategoryId,
+		UserId:       userId,
+	}
+	if !newTask.DeadlineTime.After(newTask.CreationTime) {
+		return util.Task{}, util.ErrInvalidDeadline
+	}
+
+	newTask.Id, err = s.taskDb.AddTask(ctx, logger, newTask)
+	if err != nil {
+		return util.Task{}, err
+	}
+
+	return newTask, nil
+}
+
+func (s *TaskService) GetTask(ctx context.Context, logger *slog.Logger, userId uint64, taskId uint64) (util.Task, error) {
+	if userId < 1 {
+		return util.Task{}, util.ErrInvalidUserId
+	}
+	if taskId < 1 {
+		return util.Task{}, util.ErrInvalidTaskId
+	}
+
+	task, err := s.taskDb.GetTask(ctx, logger, taskId, userId)
+	if err != nil {
+		return task, err
+	}
+
+	return task, nil
+}
+
+// QueryTasks returns a page of the user's tasks matching the querySettings.
+// A page of 0 is treated as the first page and a sort type without an order is sorted ascending.
+func (s *TaskService) QueryTasks(ctx context.Context, logger *slog.Logger, userId uint64, querySettings util.TaskQuerySettings) ([]util.Task, error) {
+	if userId < 1 {
+		return nil, util.ErrInvalidUserId
+	}
+
+	querySettings.UserId = userId
+	if querySettings.Page < 1 {
+		querySettings.Page = 1
+	}
+	if querySettings.SortType != 0 && querySettings.SortOrder == 0 {
+		querySettings.SortOrder = util.ORDER_ASCEDNING
+	}
+
+	tasks, err := s.taskDb.QueryTask(ctx, logger, querySettings)
+	if err != nil {
+		return tasks, err
+	}
+
+	return tasks, nil
+}
+
+// EditTask changes the name, deadline and category of the task specified by task.Id.
+// Fields of the task left as their zero value are not changed.
+func (s *TaskService) EditTask(ctx context.Context, logger *slog.Logger, userId uint64, task util.Task) (util.Task, error) {
+	if userId < 1 {
+		return util.Task{}, util.ErrInvalidUserId
+	}
+	if task.Id < 1 {
+		return util.Task{}, util.ErrInvalidTaskId
+	}
+
+	current, err := s.taskDb.GetTask(ctx, logger, task.Id, userId)
+	if err != nil {
+		return util.Task{}, err
+	}
+
+	edit := util.Task{
+		Id:     task.Id,
+		Name:   task.Name,
+		UserId: userId,
+	}
+
+	oldDeadline := current.DeadlineTime
+	if !task.DeadlineTime.IsZero() {
+		edit.DeadlineTime = task.DeadlineTime.UTC()
+		if !edit.DeadlineTime.After(current.CreationTime) {
+			return util.Task{}, util.ErrInvalidDeadline
+		}
+		current.DeadlineTime = edit.DeadlineTime
+	}
+
+	if task.CategoryId != 0 {
+		_, err = s.categoryDb.GetCategoryById(ctx, logger, task.CategoryId, userId)
+		if err != nil {
+			return util.Task{}, err
+		}
+		edit.CategoryId = task.CategoryId
+		current.CategoryId = edit.CategoryId
+	}
+
+	if task.Name != "" {
+		current.Name = task.Name
+	}
+
+	err = s.taskDb.EditTask(ctx, logger, edit)
+	if err != nil {
+		return util.Task{}, err
+	}
+
+	// Task and deadline goals counted the task in the periods of its old deadline and category
+	if edit.CategoryId != 0 {
+		err = s.streakDb.ResetStreaks(ctx, logger, userId, current.CreationTime)
+	} else if !edit.DeadlineTime.IsZero() {
+		from := oldDeadline
+		if edit.DeadlineTime.Before(from) {
+			from = edit.DeadlineTime
+		}
+		err = s.streakDb.ResetStreaks(ctx, logger, userId, from)
+	}
+	if err != nil {
+		return util.Task{}, err
+	}
+
+	return current, nil
+}
+
+func (s *TaskService) DeleteTask(ctx context.Context, logger *slog.Logger, userId uint64, taskId uint64) error {
+	if userId < 1 {
+		return util.ErrInvalidUserId
+	}
+	if taskId < 1 {
+		return util.ErrInvalidTaskId
+	}
+
+	task, err := s.taskDb.GetTask(ctx, logger, taskId, userId)
+	if err != nil {
+		return err
+	}
+
+	err = s.taskDb.DeleteTask(ctx, logger, taskId, userId)
+	if err != nil {
+		return err
+	}
+
+	return s.streakDb.ResetStreaks(ctx, logger, userId, task.CreationTime)
+}
+
+// SetCompletion marks the task as complete or incomplete.
+// The completion time of the task is set to now when completed and cleared otherwise.
+func (s *TaskService) SetCompletion(ctx context.Context, logger *slog.Logger, userId uint64, taskId uint64, isComplete bool) (util.Task, error) {
+	if userId < 1 {
+		return util.Task{}, util.ErrInvalidUserId
+	}
+	if taskId < 1 {
+		return util.Task{}, util.ErrInvalidTaskId
+	}
+
+	task, err := s.taskDb.GetTask(ctx, logger, taskId, userId)
+	if err != nil {
+		return util.Task{}, err
+	}
+
+	completionTime := time.Time{}
+	if isComplete {
+		completionTime = time.Now().UTC()
+	}
+
+	err = s.taskDb.SetTaskCompletion(ctx, logger, taskId, isComplete, completionTime, userId)
+	if err != nil {
+		return util.Task{}, err
+	}
+
+	// Task and deadline goals counted the task in the periods of its old completion and its deadline
+	if task.IsComplete && !isComplete {
+		from := task.CompletionTime
+		if task.DeadlineTime.Before(from) {
+			from = task.DeadlineTime
+		}
+		err = s.streakDb.ResetStreaks(ctx, logger, userId, from)
+		if err != nil {
+			return util.Task{}, err
+		}
+	}
+
+	return s.taskDb.GetTask(ctx, logger, taskId, userId)
+}
@@ -0,0 +1,259 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/NerdBow/Grinders-API/internal/database"
+	"github.com/NerdBow/Grinders-API/internal/util"
+)
+
+const (
+	TIMER_LIMIT_INTERVAL = time.Minute // How often the background job stops work logs that reached their limit
+	CUTOFF_FORMAT        = "15:04"
+	MIN_TIMER_LIMIT      = 60           // In seconds
+	MAX_TIMER_LIMIT      = 24 * 60 * 60 // In seconds
+)
+
+// TimerLimitService stops forgotten work logs once they run for longer than the maximum duration of the user
+// or reach their daily cut-off time. Work logs that were stopped this way need to be reviewed by the user.
+type TimerLimitService struct {
+	limitDb    database.TimerLimitsDB
+	settingsDb database.UserSettingsDB
+	workLogDb  database.WorkLogsDB
+	pauseDb    database.PausesDB
+	streakDb   database.StreaksDB
+}
+
+func NewTimerLimitService(limitDb database.TimerLimitsDB, settingsDb database.UserSettingsDB, workLogDb database.WorkLogsDB, pauseDb database.PausesDB, streakDb database.StreaksDB) TimerLimitService {
+	return TimerLimitService{
+		limitDb:    limitDb,
+		settingsDb: settingsDb,
+		workLogDb:  workLogDb,
+		pauseDb:    pauseDb,
+		streakDb:   streakDb,
+	}
+}
+
+// GetLimits returns the timer limits of the user.
+func (s *TimerLimitService) GetLimits(ctx context.Context, logger *slog.Logger, userId uint64) (util.TimerLimits, error) {
+	if userId < 1 {
+		return util.TimerLimits{}, util.ErrInvalidUserId
+	}
+
+	return s.limitDb.GetTimerLimits(ctx, logger, userId)
+}
+
+// SetLimits replaces the timer limits of the user.
+// The cut-off time is in the time zone of the user settings.
+func (s *TimerLimitService) SetLimits(ctx context.Context, logger *slog.Logger, userId uint64, limits util.TimerLimits) (util.TimerLimits, error) {
+	if userId < 1 {
+		return util.TimerLimits{}, util.ErrInvalidUserId
+	}
+	if limits.MaxDuration != 0 && (limits.MaxDuration < MIN_TIMER_LIMIT || limits.MaxDuration > MAX_TIMER_LIMIT) {
+		return util.TimerLimits{}, util.ErrInvalidLimits
+	}
+	if limits.CutoffTime != "" {
+		if _, err := time.Parse(CUTOFF_FORMAT, limits.CutoffTime); err != nil {
+			return util.TimerLimits{}, util.ErrInvalidLimits
+		}
+	}
+
+	limits.UserId = userId
+	err := s.limitDb.SetTimerLimits(ctx, logger, limits)
+	if err != nil {
+		return util.TimerLimits{}, err
+	}
+
+	return limits, nil
+}
+
+// GetReviews returns the work logs of the user that were stopped automatically and have not been reviewed yet.
+func (s *TimerLimitService) GetReviews(ctx context.Context, logger *slog.Logger, userId uint64) ([]util.WorkLogReview, error) {
+	if userId < 1 {
+		return nil, util.ErrInvalidUserId
+	}
+
+	return s.limitDb.GetWorkLogReviews(ctx, logger, userId)
+}
+
+// ResolveReview marks the automatically stopped work log as reviewed.
+// The end time can be corrected to an earlier time by passing a non zero endTime
+// and the work description is replaced if it is not empty.
+func (s *TimerLimitService) ResolveReview(ctx context.Context, logger *slog.Logger, userId uint64, workLogId uint64, endTime time.Time, description string) (util.WorkLog, error) {
+	if userId < 1 {
+		return util.WorkLog{}, util.ErrInvalidUserId
+	}
+	if workLogId < 1 {
+		return util.WorkLog{}, util.ErrInvalidWorkLogId
+	}
+
+	review, err := s.limitDb.GetWorkLogReview(ctx, logger, workLogId, userId)
+	if err != nil {
+		return util.WorkLog{}, err
+	}
+
+	workLog := review.WorkLog
+	if description != "" {
+		workLog.WorkDescription = description
+	}
+	if !endTime.IsZero() {
+		endTime = endTime.UTC()
+		if endTime.Before(workLog.StartTime) || endTime.After(review.AutoStopTime) {
+			return util.WorkLog{}, util.ErrInvalidReviewEnd
+		}
+
+		pauses, err := s.pauseDb.GetPauses(ctx, logger, workLog.Id)
+		if err != nil {
+			return util.WorkLog{}, err
+		}
+
+		workLog.EndTime = endTime
+		workLog.Duration = focusedDuration(workLog.StartTime, endTime, pauses)
+	}
+
+	err = s.limitDb.ResolveWorkLogReview(ctx, logger, workLog)
+	if err != nil {
+		return util.WorkLog{}, err
+	}
+
+	err = s.streakDb.ResetStreaks(ctx, logger, userId, workLog.StartTime)
+	if err != nil {
+		return util.WorkLog{}, err
+	}
+
+	return workLog, nil
+}
+
+// StopLimitedWorkLogs stops every running work log that reached a limit of its user at the time it was reached.
+// Failures are logged and do not stop the other work logs from being stopped.
+func (s *TimerLimitService) StopLimitedWorkLogs(ctx context.Context, logger *slog.Logger) {
+	now := time.Now().UTC()
+	workLogs, err := s.limitDb.GetLimitedWorkLogs(ctx, logger)
+	if err != nil {
+		return
+	}
+
+	for _, workLog := range workLogs {
+		err = s.stopLimitedWorkLog(ctx, logger, workLog, now)
+		if err != nil {
+			logger.LogAttrs(ctx, slog.LevelWarn, "StopLimitedWorkLogs", slog.Uint64("workLogId", workLog.Id), slog.String("err", err.Error()))
+		}
+	}
+}
+
+// stopLimitedWorkLog stops the running work log if it reached a limit by now.
+func (s *TimerLimitService) stopLimitedWorkLog(ctx context.Context, logger *slog.Logger, workLog util.WorkLog, now time.Time) error {
+	limits, err := s.limitDb.GetTimerLimits(ctx, logger, workLog.UserId)
+	if err != nil {
+		return err
+	}
+
+	loc, err := userLocation(ctx, logger, s.settingsDb, workLog.UserId)
+	if err != nil {
+		return err
+	}
+
+	pauses, err := s.pauseDb.GetPauses(ctx, logger, workLog.Id)
+	if err != nil {
+		return err
+	}
+
+	stopTime, reason := limitStop(workLog.StartTime, pauses, limits, loc)
+	if stopTime.IsZero() || stopTime.After(now) {
+		return nil
+	}
+
+	pause, err := s.pauseDb.GetRunningPause(ctx, logger, workLog.Id)
+	if err != nil && !errors.Is(err, util.ErrWorkLogNotPaused) {
+		return err
+	}
+	if pause.Id != 0 {
+		pause.EndTime = stopTime
+		if pause.StartTime.After(stopTime) {
+			pause.EndTime = pause.StartTime
+		}
+		pause.Duration = int64(pause.EndTime.Sub(pause.StartTime) / time.Second)
+	}
+
+	workLog.IsComplete = true
+	workLog.EndTime = stopTime
+	workLog.Duration = focusedDuration(workLog.StartTime, stopTime, pauses)
+
+	err = s.limitDb.AutoStopWorkLog(ctx, logger, workLog, pause, reason)
+	if err != nil {
+		return err
+	}
+
+	// The work log may have been counted as running past its stop time
+	err = s.streakDb.ResetStreaks(ctx, logger, workLog.UserId, workLog.StartTime)
+	if err != nil {
+		return err
+	}
+
+	logger.LogAttrs(ctx, slog.LevelInfo, "Auto stopped work log", slog.Uint64("workLogId", workLog.Id), slog.String("reason", reason))
+	return nil
+}
+
+// limitStop returns when a work log started at startTime with the pauses reaches its first limit and the reason for it.
+// The zero time is returned if it will never reach one.
+func limitStop(startTime time.Time, pauses []util.Pause, limits util.TimerLimits, loc *time.Location) (time.Time, string) {
+	stopTime := time.Time{}
+	reason := ""
+
+	if limits.MaxDuration > 0 {
+		stopTime = focusedUntil(startTime, pauses, time.Duration(limits.MaxDuration)*time.Second)
+		reason = util.REVIEW_MAX_DURATION
+	}
+
+	if cutoff, err := time.Parse(CUTOFF_FORMAT, limits.CutoffTime); err == nil {
+		local := startTime.In(loc)
+		cutoffTime := time.Date(local.Year(), local.Month(), local.Day(), cutoff.Hour(), cutoff.Minute(), 0, 0, loc)
+		if !cutoffTime.After(startTime) {
+			cutoffTime = cutoffTime.AddDate(0, 0, 1)
+		}
+		if stopTime.IsZero() || cutoffTime.Before(stopTime) {
+			stopTime = cutoffTime.UTC()
+			reason = util.REVIEW_CUTOFF
+		}
+	}
+
+	return stopTime, reason
+}
+
+// focusedUntil returns when a work log started at startTime with the pauses, ordered by start time, has been focused for focused.
+// The zero time is returned if it is paused before then and the pause has not ended.
+func focusedUntil(startTime time.Time, pauses []util.Pause, focused time.Duration) time.Time {
+	cursor := startTime
+	for _, pause := range pauses {
+		if pause.StartTime.Sub(cursor) >= focused {
+			break
+		}
+		if pause.StartTime.After(cursor) {
+			focused -= pause.StartTime.Sub(cursor)
+		}
+		if pause.EndTime.IsZero() {
+			return time.Time{}
+		}
+		if pause.EndTime.After(cursor) {
+			cursor = pause.EndTime
+		}
+	}
+	return cursor.Add(focused)
+}
+
+// focusedDuration returns the seconds from startTime to endTime that are not covered by the pauses.
+// Running pauses are counted up to endTime.
+func focusedDuration(startTime time.Time, endTime time.Time, pauses []util.Pause) int64 {
+	paused := time.Duration(0)
+	for _, pause := range pauses {
+		pauseEnd := pause.EndTime
+		if pauseEnd.IsZero() {
+			pauseEnd = endTime
+		}
+		paused += overlap(pause.StartTime, pauseEnd, startTime, endTime)
+	}
+	return int64((endTime.Sub(startTime) - paused) / time.Second)
+}
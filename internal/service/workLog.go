@@ -0,0 +1,512 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/NerdBow/Grinders-API/internal/database"
+	"github.com/NerdBow/Grinders-API/internal/util"
+)
+
+const MAX_MERGED_WORK_LOGS = 50
+
+type WorkLogService struct {
+	workLogDb database.WorkLogsDB
+	pauseDb   database.PausesDB
+	taskDb    database.TasksDB
+	streakDb  database.StreaksDB
+}
+
+func NewWorkLogService(workLogDb database.WorkLogsDB, pauseDb database.PausesDB, taskDb database.TasksDB, streakDb database.StreaksDB) WorkLogService {
+	return WorkLogService{
+		workLogDb: workLogDb,
+		pauseDb:   pauseDb,
+		taskDb:    taskDb,
+		streakDb:  streakDb,
+	}
+}
+
+// StartWorkLog starts the timer of a new work log on the task with the given objective.
+// A user can only have one running work log. If one is already running, a ConflictError of ErrWorkLogConflict
+// holding it is returned, unless switchRunning is set, then it is stopped in the same transaction the new one is started in.
+func (s *WorkLogService) StartWorkLog(ctx context.Context, logger *slog.Logger, userId uint64, taskId uint64, objective string, switchRunning bool) (util.WorkLog, error) {
+	if userId < 1 {
+		return util.WorkLog{}, util.ErrInvalidUserId
+	}
+	if taskId < 1 {
+		return util.WorkLog{}, util.ErrInvalidTaskId
+	}
+	if objective == "" {
+		return util.WorkLog{}, fmt.Errorf("%w for an objective", util.ErrEmptyString)
+	}
+
+	_, err := s.taskDb.GetTask(ctx, logger, taskId, userId)
+	if err != nil {
+		return util.WorkLog{}, err
+	}
+
+	workLog := util.WorkLog{
+		TaskId:    taskId,
+		Objective: objective,
+		StartTime: time.Now().UTC(),
+		UserId:    userId,
+	}
+
+	running, err := s.workLogDb.GetRunningWorkLog(ctx, logger, userId)
+	switch {
+	case errors.Is(err, util.ErrWorkLogNotFound):
+		workLog.Id, err = s.workLogDb.AddWorkLog(ctx, logger, workLog)
+	case err != nil:
+		return util.WorkLog{}, err
+	case !switchRunning:
+		return util.WorkLog{}, &util.ConflictError{Err: util.ErrWorkLogConflict, Conflict: running}
+	default:
+		workLog.Id, err = s.switchWorkLog(ctx, logger, running, workLog)
+	}
+
+	// Another request started a work log in between, so report that one as the conflict.
+	if errors.Is(err, util.ErrWorkLogConflict) {
+		running, runningErr := s.workLogDb.GetRunningWorkLog(ctx, logger, userId)
+		if runningErr != nil {
+			return util.WorkLog{}, err
+		}
+		return util.WorkLog{}, &util.ConflictError{Err: util.ErrWorkLogConflict, Conflict: running}
+	}
+	if err != nil {
+		return util.WorkLog{}, err
+	}
+
+	return workLog, nil
+}
+
+// AddWorkLog records a work log that was not timed, from startTime to endTime.
+// It must end by now and not overlap the user's other work logs. If it does, a ConflictError of ErrWorkLogOverlap
+// holding the intervals of the overlapping work logs is returned.
+func (s *WorkLogService) AddWorkLog(ctx context.Context, logger *slog.Logger, userId uint64, workLog util.WorkLog) (util.WorkLog, error) {
+	if userId < 1 {
+		return util.WorkLog{}, util.ErrInvalidUserId
+	}
+	if workLog.TaskId < 1 {
+		return util.WorkLog{}, util.ErrInvalidTaskId
+	}
+	if workLog.Objective == "" {
+		return util.WorkLog{}, fmt.Errorf("%w for an objective", util.ErrEmptyString)
+	}
+	if !workLog.StartTime.Before(workLog.EndTime) {
+		return util.WorkLog{}, util.ErrInvalidTimeRange
+	}
+
+	now := time.Now().UTC()
+	if workLog.EndTime.After(now) {
+		return util.WorkLog{}, util.ErrWorkLogInFuture
+	}
+
+	_, err := s.taskDb.GetTask(ctx, logger, workLog.TaskId, userId)
+	if err != nil {
+		return util.WorkLog{}, err
+	}
+
+	workLog.Id = 0
+	workLog.UserId = userId
+	workLog.IsComplete = true
+	workLog.StartTime = workLog.StartTime.UTC()
+	workLog.EndTime = workLog.EndTime.UTC()
+	workLog.Duration = int64(workLog.EndTime.Sub(workLog.StartTime) / time.Second)
+
+	id, overlaps, err := s.workLogDb.AddCompletedWorkLog(ctx, logger, workLog)
+	if err != nil {
+		return util.WorkLog{}, err
+	}
+	if len(overlaps) > 0 {
+		intervals := make([]util.WorkLogInterval, 0, len(overlaps))
+		for _, overlap := range overlaps {
+			endTime := overlap.EndTime
+			if !overlap.IsComplete {
+				endTime = now
+			}
+			intervals = append(intervals, util.WorkLogInterval{WorkLogId: overlap.Id, StartTime: overlap.StartTime, EndTime: endTime})
+		}
+		return util.WorkLog{}, &util.ConflictError{Err: util.ErrWorkLogOverlap, Conflict: intervals}
+	}
+
+	workLog.Id = id
+
+	err = s.streakDb.ResetStreaks(ctx, logger, userId, workLog.StartTime)
+	if err != nil {
+		return util.WorkLog{}, err
+	}
+
+	return workLog, nil
+}
+
+// StopWorkLog stops the timer of the running work log and records what was done in the description.
+// A running pause is ended at the same time. The duration of the work log excludes all of its pauses.
+func (s *WorkLogService) StopWorkLog(ctx context.Context, logger *slog.Logger, userId uint64, workLogId uint64, description string) (util.WorkLog, error) {
+	workLog, err := s.getRunningWorkLog(ctx, logger, userId, workLogId)
+	if err != nil {
+		return util.WorkLog{}, err
+	}
+
+	return s.stop(ctx, logger, workLog, description, time.Now().UTC())
+}
+
+// PauseWorkLog pauses the timer of the running work log until it is resumed.
+func (s *WorkLogService) PauseWorkLog(ctx context.Context, logger *slog.Logger, userId uint64, workLogId uint64) (util.Pause, error) {
+	workLog, err := s.getRunningWorkLog(ctx, logger, userId, workLogId)
+	if err != nil {
+		return util.Pause{}, err
+	}
+
+	pause := util.Pause{
+		StartTime: time.Now().UTC(),
+		WorkLogId: workLog.Id,
+	}
+
+	pause.Id, err = s.pauseDb.AddPause(ctx, logger, pause)
+	if err != nil {
+		return util.Pause{}, err
+	}
+
+	return pause, nil
+}
+
+// ResumeWorkLog ends the running pause of the work log.
+func (s *WorkLogService) ResumeWorkLog(ctx context.Context, logger *slog.Logger, userId uint64, workLogId uint64) (util.Pause, error) {
+	workLog, err := s.getRunningWorkLog(ctx, logger, userId, workLogId)
+	if err != nil {
+		return util.Pause{}, err
+	}
+
+	pause, err := s.pauseDb.GetRunningPause(ctx, logger, workLog.Id)
+	if err != nil {
+		return util.Pause{}, err
+	}
+
+	pause.EndTime = time.Now().UTC()
+	pause.Duration = int64(pause.EndTime.Sub(pause.StartTime) / time.Second)
+
+	err = s.pauseDb.EndPause(ctx, logger, pause)
+	if err != nil {
+		return util.Pause{}, err
+	}
+
+	return pause, nil
+}
+
+// SplitWorkLog splits the stopped work log at splitTime into two work logs.
+// The second one is on the task with taskId and has the objective, both default to those of the work log if they are 0 or empty.
+// A pause spanning splitTime is split as well, breaks move to the second work log if they start after splitTime.
+func (s *WorkLogService) SplitWorkLog(ctx context.Context, logger *slog.Logger, userId uint64, workLogId uint64, splitTime time.Time, taskId uint64, objective string) ([]util.WorkLog, error) {
+	if userId < 1 {
+		return nil, util.ErrInvalidUserId
+	}
+	if workLogId < 1 {
+		return nil, util.ErrInvalidWorkLogId
+	}
+
+	first, err := s.workLogDb.GetWorkLog(ctx, logger, workLogId, userId)
+	if err != nil {
+		return nil, err
+	}
+	if !first.IsComplete {
+		return nil, util.ErrWorkLogNotStopped
+	}
+	splitTime = splitTime.UTC()
+	if !splitTime.After(first.StartTime) || !splitTime.Before(first.EndTime) {
+		return nil, util.ErrInvalidSplitTime
+	}
+
+	second := first
+	second.Id = 0
+	if taskId != 0 && taskId != first.TaskId {
+		_, err = s.taskDb.GetTask(ctx, logger, taskId, userId)
+		if err != nil {
+			return nil, err
+		}
+		second.TaskId = taskId
+	}
+	if objective != "" {
+		second.Objective = objective
+	}
+
+	pauses, err := s.pauseDb.GetPauses(ctx, logger, first.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	firstPauses := make([]util.Pause, 0, len(pauses))
+	secondPauses := make([]util.Pause, 0, len(pauses))
+	for _, pause := range pauses {
+		switch {
+		case !pause.EndTime.After(splitTime):
+			firstPauses = append(firstPauses, pause)
+		case !pause.StartTime.Before(splitTime):
+			secondPauses = append(secondPauses, pause)
+		default:
+			secondPauses = append(secondPauses, util.Pause{
+				StartTime: splitTime,
+				Duration:  int64(pause.EndTime.Sub(splitTime) / time.Second),
+				EndTime:   pause.EndTime,
+			})
+			pause.EndTime = splitTime
+			pause.Duration = int64(pause.EndTime.Sub(pause.StartTime) / time.Second)
+			firstPauses = append(firstPauses, pause)
+		}
+	}
+
+	first.EndTime = splitTime
+	first.Duration = focusedDuration(first.StartTime, first.EndTime, firstPauses)
+	second.StartTime = splitTime
+	second.Duration = focusedDuration(second.StartTime, second.EndTime, secondPauses)
+
+	second.Id, err = s.workLogDb.SplitWorkLog(ctx, logger, first, second, firstPauses, secondPauses)
+	if err != nil {
+		return nil, err
+	}
+
+	// The second work log can be on a task of another category, which changes the progress of category goals.
+	err = s.streakDb.ResetStreaks(ctx, logger, userId, splitTime)
+	if err != nil {
+		return nil, err
+	}
+
+	return []util.WorkLog{first, second}, nil
+}
+
+// MergeWorkLogs merges the stopped work logs on the same task into the earliest of them.
+// No other work log may start between them and none of them may need a review, the time between them becomes pauses of the merged work log.
+// The distinct objectives and work descriptions are concatenated in order of the start time.
+func (s *WorkLogService) MergeWorkLogs(ctx context.Context, logger *slog.Logger, userId uint64, workLogIds []uint64) (util.WorkLog, error) {
+	if userId < 1 {
+		return util.WorkLog{}, util.ErrInvalidUserId
+	}
+
+	workLogs := make([]util.WorkLog, 0, len(workLogIds))
+	for _, workLogId := range workLogIds {
+		if workLogId < 1 {
+			return util.WorkLog{}, util.ErrInvalidWorkLogId
+		}
+		if slices.ContainsFunc(workLogs, func(workLog util.WorkLog) bool { return workLog.Id == workLogId }) {
+			continue
+		}
+
+		workLog, err := s.workLogDb.GetWorkLog(ctx, logger, workLogId, userId)
+		if err != nil {
+			return util.WorkLog{}, err
+		}
+		if !workLog.IsComplete {
+			return util.WorkLog{}, util.ErrWorkLogNotStopped
+		}
+		workLogs = append(workLogs, workLog)
+	}
+	if len(workLogs) < 2 || len(workLogs) > MAX_MERGED_WORK_LOGS {
+		return util.WorkLog{}, util.ErrMergeTooFew
+	}
+
+	slices.SortFunc(workLogs, func(a util.WorkLog, b util.WorkLog) int {
+		return a.StartTime.Compare(b.StartTime)
+	})
+
+	merged := workLogs[0]
+	objectives := []string{merged.Objective}
+	descriptions := []string{merged.WorkDescription}
+	otherIds := make([]uint64, 0, len(workLogs)-1)
+	gapPauses := make([]util.Pause, 0, len(workLogs)-1)
+	for _, workLog := range workLogs[1:] {
+		if workLog.TaskId != merged.TaskId {
+			return util.WorkLog{}, util.ErrMergeTaskMismatch
+		}
+		if workLog.StartTime.Before(merged.EndTime) {
+			return util.WorkLog{}, util.ErrMergeNotAdjacent
+		}
+
+		if workLog.StartTime.After(merged.EndTime) {
+			gapPauses = append(gapPauses, util.Pause{
+				StartTime: merged.EndTime,
+				Duration:  int64(workLog.StartTime.Sub(merged.EndTime) / time.Second),
+				EndTime:   workLog.StartTime,
+			})
+		}
+		if !slices.Contains(objectives, workLog.Objective) {
+			objectives = append(objectives, workLog.Objective)
+		}
+		if !slices.Contains(descriptions, workLog.WorkDescription) {
+			descriptions = append(descriptions, workLog.WorkDescription)
+		}
+
+		merged.EndTime = workLog.EndTime
+		merged.Duration += workLog.Duration
+		otherIds = append(otherIds, workLog.Id)
+	}
+	merged.Objective = strings.Join(objectives, "; ")
+	merged.WorkDescription = strings.TrimSpace(strings.Join(descriptions, "\n"))
+
+	err := s.workLogDb.MergeWorkLogs(ctx, logger, merged, otherIds, gapPauses)
+	if err != nil {
+		return util.WorkLog{}, err
+	}
+
+	return merged, nil
+}
+
+// GetWorkLog returns the work log with its pause timeline.
+func (s *WorkLogService) GetWorkLog(ctx context.Context, logger *slog.Logger, userId uint64, workLogId uint64) (util.WorkLogTimeline, error) {
+	if userId < 1 {
+		return util.WorkLogTimeline{}, util.ErrInvalidUserId
+	}
+	if workLogId < 1 {
+		return util.WorkLogTimeline{}, util.ErrInvalidWorkLogId
+	}
+
+	workLog, err := s.workLogDb.GetWorkLog(ctx, logger, workLogId, userId)
+	if err != nil {
+		return util.WorkLogTimeline{}, err
+	}
+
+	pauses, err := s.pauseDb.GetPauses(ctx, logger, workLog.Id)
+	if err != nil {
+		return util.WorkLogTimeline{}, err
+	}
+
+	return newTimeline(workLog, pauses, time.Now().UTC()), nil
+}
+
+// QueryWorkLogs returns a page of the user's work logs of a task and/or started within a time range.
+// A page of 0 is treated as the first page.
+func (s *WorkLogService) QueryWorkLogs(ctx context.Context, logger *slog.Logger, userId uint64, querySettings util.WorkLogQuerySettings) ([]util.WorkLog, error) {
+	if userId < 1 {
+		return nil, util.ErrInvalidUserId
+	}
+	if !querySettings.From.IsZero() && !querySettings.To.IsZero() && !querySettings.From.Before(querySettings.To) {
+		return nil, util.ErrInvalidTimeRange
+	}
+
+	querySettings.UserId = userId
+	querySettings.From = querySettings.From.UTC()
+	querySettings.To = querySettings.To.UTC()
+	if querySettings.Page < 1 {
+		querySettings.Page = 1
+	}
+
+	workLogs, err := s.workLogDb.QueryWorkLogs(ctx, logger, querySettings)
+	if err != nil {
+		return workLogs, err
+	}
+
+	return workLogs, nil
+}
+
+// getRunningWorkLog returns the work log if it belongs to the user and has not been stopped yet.
+func (s *WorkLogService) getRunningWorkLog(ctx context.Context, logger *slog.Logger, userId uint64, workLogId uint64) (util.WorkLog, error) {
+	if userId < 1 {
+		return util.WorkLog{}, util.ErrInvalidUserId
+	}
+	if workLogId < 1 {
+		return util.WorkLog{}, util.ErrInvalidWorkLogId
+	}
+
+	workLog, err := s.workLogDb.GetWorkLog(ctx, logger, workLogId, userId)
+	if err != nil {
+		return util.WorkLog{}, err
+	}
+	if workLog.IsComplete {
+		return util.WorkLog{}, util.ErrWorkLogStopped
+	}
+
+	return workLog, nil
+}
+
+// switchWorkLog stops the running work log when the new workLog starts and starts it in one transaction.
+func (s *WorkLogService) switchWorkLog(ctx context.Context, logger *slog.Logger, running util.WorkLog, workLog util.WorkLog) (uint64, error) {
+	pause, err := s.pauseDb.GetRunningPause(ctx, logger, running.Id)
+	if err != nil && !errors.Is(err, util.ErrWorkLogNotPaused) {
+		return 0, err
+	}
+	if pause.Id != 0 {
+		pause.EndTime = workLog.StartTime
+		pause.Duration = int64(pause.EndTime.Sub(pause.StartTime) / time.Second)
+	}
+
+	pauses, err := s.pauseDb.GetPauses(ctx, logger, running.Id)
+	if err != nil {
+		return 0, err
+	}
+
+	running.IsComplete = true
+	running.EndTime = workLog.StartTime
+	running.Duration = newTimeline(running, pauses, workLog.StartTime).FocusedDuration
+
+	return s.workLogDb.SwitchWorkLog(ctx, logger, running, pause, workLog)
+}
+
+// stop stops the running work log at endTime after ending its running pause.
+func (s *WorkLogService) stop(ctx context.Context, logger *slog.Logger, workLog util.WorkLog, description string, endTime time.Time) (util.WorkLog, error) {
+	err := s.endRunningPause(ctx, logger, workLog.Id, endTime)
+	if err != nil {
+		return util.WorkLog{}, err
+	}
+
+	pauses, err := s.pauseDb.GetPauses(ctx, logger, workLog.Id)
+	if err != nil {
+		return util.WorkLog{}, err
+	}
+
+	workLog.WorkDescription = description
+	workLog.IsComplete = true
+	workLog.EndTime = endTime
+	workLog.Duration = newTimeline(workLog, pauses, endTime).FocusedDuration
+
+	err = s.workLogDb.StopWorkLog(ctx, logger, workLog)
+	if err != nil {
+		return util.WorkLog{}, err
+	}
+
+	return workLog, nil
+}
+
+// endRunningPause ends the running pause of the work log at endTime if there is one.
+func (s *WorkLogService) endRunningPause(ctx context.Context, logger *slog.Logger, workLogId uint64, endTime time.Time) error {
+	pause, err := s.pauseDb.GetRunningPause(ctx, logger, workLogId)
+	if errors.Is(err, util.ErrWorkLogNotPaused) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	pause.EndTime = endTime
+	pause.Duration = int64(pause.EndTime.Sub(pause.StartTime) / time.Second)
+	return s.pauseDb.EndPause(ctx, logger, pause)
+}
+
+// newTimeline calculates the durations of the work log and its pauses.
+// Anything still running is counted up to now.
+func newTimeline(workLog util.WorkLog, pauses []util.Pause, now time.Time) util.WorkLogTimeline {
+	endTime := workLog.EndTime
+	if !workLog.IsComplete {
+		endTime = now
+	}
+
+	var paused time.Duration
+	for _, pause := range pauses {
+		pauseEnd := pause.EndTime
+		if pauseEnd.IsZero() {
+			pauseEnd = endTime
+		}
+		paused += pauseEnd.Sub(pause.StartTime)
+	}
+	wall := endTime.Sub(workLog.StartTime)
+
+	return util.WorkLogTimeline{
+		WorkLog:         workLog,
+		Pauses:          pauses,
+		WallDuration:    int64(wall / time.Second),
+		PausedDuration:  int64(paused / time.Second),
+		FocusedDuration: int64((wall - paused) / time.Second),
+	}
+}
@@ -0,0 +1,436 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/NerdBow/Grinders-API/internal/database/sqlite"
+	"github.com/NerdBow/Grinders-API/internal/util"
+)
+
+// span is the time a pause covers, without the ids the database gives it.
+type span struct {
+	start time.Time
+	end   time.Time
+}
+
+func (s span) String() string {
+	return s.start.Format("15:04") + "-" + s.end.Format("15:04")
+}
+
+func pauseSpans(pauses []util.Pause) []span {
+	spans := make([]span, 0, len(pauses))
+	for _, pause := range pauses {
+		spans = append(spans, span{start: pause.StartTime, end: pause.EndTime})
+	}
+	return spans
+}
+
+func spanEqual(a span, b span) bool {
+	return a.start.Equal(b.start) && a.end.Equal(b.end)
+}
+
+func testLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+// march returns the start of the day in March 2026, which starts on a Sunday.
+func march(day int) time.Time {
+	return time.Date(2026, time.March, day, 0, 0, 0, 0, time.UTC)
+}
+
+// clock returns the time of day on March 2nd 2026.
+func clock(hour int, minute int) time.Time {
+	return march(2).Add(time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute)
+}
+
+// newWorkLogTestService returns a WorkLogService on an empty database in a temporary directory.
+// The database has user 1 with the tasks 1 and 2.
+func newWorkLogTestService(t *testing.T) (WorkLogService, *sqlite.SQLiteDB) {
+	t.Helper()
+	ctx := context.Background()
+	logger := testLogger()
+
+	db, err := sqlite.NewSQLiteDB(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("NewSQLiteDB returned %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	if err := db.CreateTables(); err != nil {
+		t.Fatalf("CreateTables returned %v", err)
+	}
+	if err := db.AddUser(ctx, logger, util.User{Username: "user", Hash: "hash", CreationTime: march(1)}); err != nil {
+		t.Fatalf("AddUser returned %v", err)
+	}
+	if err := db.AddCategory(ctx, logger, "category", 1); err != nil {
+		t.Fatalf("AddCategory returned %v", err)
+	}
+	for _, name := range []string{"first", "second"} {
+		task := util.Task{Name: name, CreationTime: march(1), DeadlineTime: march(31), CategoryId: 1, UserId: 1}
+		if _, err := db.AddTask(ctx, logger, task); err != nil {
+			t.Fatalf("AddTask returned %v", err)
+		}
+	}
+
+	return NewWorkLogService(&db, &db, &db, &db), &db
+}
+
+// addCompleteWorkLog adds a stopped work log of user 1 with the pauses and returns its id.
+func addCompleteWorkLog(t *testing.T, db *sqlite.SQLiteDB, taskId uint64, objective string, start time.Time, end time.Time, pauses []span) uint64 {
+	t.Helper()
+	ctx := context.Background()
+	logger := testLogger()
+
+	paused := time.Duration(0)
+	for _, pause := range pauses {
+		paused += pause.end.Sub(pause.start)
+	}
+	workLog := util.WorkLog{
+		TaskId:     taskId,
+		Objective:  objective,
+		IsComplete: true,
+		StartTime:  start,
+		Duration:   int64((end.Sub(start) - paused) / time.Second),
+		EndTime:    end,
+		UserId:     1,
+	}
+
+	id, err := db.AddWorkLog(ctx, logger, workLog)
+	if err != nil {
+		t.Fatalf("AddWorkLog returned %v", err)
+	}
+	for _, pause := range pauses {
+		_, err := db.AddPause(ctx, logger, util.Pause{
+			StartTime: pause.start,
+			Duration:  int64(pause.end.Sub(pause.start) / time.Second),
+			EndTime:   pause.end,
+			WorkLogId: id,
+		})
+		if err != nil {
+			t.Fatalf("AddPause returned %v", err)
+		}
+	}
+	return id
+}
+
+func TestSplitWorkLog(t *testing.T) {
+	// The work log runs from 10:00 to 11:00 and is paused from 10:10 to 10:20 and from 10:40 to 10:50.
+	pauses := []span{{clock(10, 10), clock(10, 20)}, {clock(10, 40), clock(10, 50)}}
+
+	tests := []struct {
+		name             string
+		running          bool
+		splitTime        time.Time
+		taskId           uint64
+		objective        string
+		wantErr          error
+		wantDurations    [2]int64
+		wantFirstPauses  []span
+		wantSecondPauses []span
+		wantTaskId       uint64
+		wantObjective    string
+	}{
+		{
+			name:      "at the start",
+			splitTime: clock(10, 0),
+			wantErr:   util.ErrInvalidSplitTime,
+		},
+		{
+			name:      "at the end",
+			splitTime: clock(11, 0),
+			wantErr:   util.ErrInvalidSplitTime,
+		},
+		{
+			name:      "outside the work log",
+			splitTime: clock(9, 0),
+			wantErr:   util.ErrInvalidSplitTime,
+		},
+		{
+			name:      "running work log",
+			running:   true,
+			splitTime: clock(10, 30),
+			wantErr:   util.ErrWorkLogNotStopped,
+		},
+		{
+			name:             "between pauses",
+			splitTime:        clock(10, 30),
+			wantDurations:    [2]int64{20 * 60, 20 * 60},
+			wantFirstPauses:  pauses[:1],
+			wantSecondPauses: pauses[1:],
+			wantTaskId:       1,
+			wantObjective:    "objective",
+		},
+		{
+			name:             "across a pause",
+			splitTime:        clock(10, 15),
+			wantDurations:    [2]int64{10 * 60, 30 * 60},
+			wantFirstPauses:  []span{{clock(10, 10), clock(10, 15)}},
+			wantSecondPauses: []span{{clock(10, 15), clock(10, 20)}, pauses[1]},
+			wantTaskId:       1,
+			wantObjective:    "objective",
+		},
+		{
+			name:             "at the end of a pause",
+			splitTime:        clock(10, 20),
+			wantDurations:    [2]int64{10 * 60, 30 * 60},
+			wantFirstPauses:  pauses[:1],
+			wantSecondPauses: pauses[1:],
+			wantTaskId:       1,
+			wantObjective:    "objective",
+		},
+		{
+			name:             "onto another task",
+			splitTime:        clock(10, 30),
+			taskId:           2,
+			objective:        "other objective",
+			wantDurations:    [2]int64{20 * 60, 20 * 60},
+			wantFirstPauses:  pauses[:1],
+			wantSecondPauses: pauses[1:],
+			wantTaskId:       2,
+			wantObjective:    "other objective",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+			logger := testLogger()
+			s, db := newWorkLogTestService(t)
+
+			workLogId := addCompleteWorkLog(t, db, 1, "objective", clock(10, 0), clock(11, 0), pauses)
+			if test.running {
+				_, err := db.ExecContext(ctx, "UPDATE work_logs SET is_complete = ?, end_time = ? WHERE id = ?;", false, time.Time{}, workLogId)
+				if err != nil {
+					t.Fatalf("Exec running work log returned %v", err)
+				}
+			}
+
+			got, err := s.SplitWorkLog(ctx, logger, 1, workLogId, test.splitTime, test.taskId, test.objective)
+			if test.wantErr != nil {
+				if !errors.Is(err, test.wantErr) {
+					t.Fatalf("SplitWorkLog returned %v, want %v", err, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("SplitWorkLog returned %v", err)
+			}
+
+			first, second := got[0], got[1]
+			if !first.StartTime.Equal(clock(10, 0)) || !first.EndTime.Equal(test.splitTime) {
+				t.Errorf("first work log runs from %v to %v, want %v to %v", first.StartTime, first.EndTime, clock(10, 0), test.splitTime)
+			}
+			if !second.StartTime.Equal(test.splitTime) || !second.EndTime.Equal(clock(11, 0)) {
+				t.Errorf("second work log runs from %v to %v, want %v to %v", second.StartTime, second.EndTime, test.splitTime, clock(11, 0))
+			}
+			if first.TaskId != 1 || first.Objective != "objective" {
+				t.Errorf("first work log is on task %d with objective %q, want task 1 with objective %q", first.TaskId, first.Objective, "objective")
+			}
+			if second.TaskId != test.wantTaskId || second.Objective != test.wantObjective {
+				t.Errorf("second work log is on task %d with objective %q, want task %d with objective %q", second.TaskId, second.Objective, test.wantTaskId, test.wantObjective)
+			}
+
+			for i, workLog := range got {
+				stored, err := db.GetWorkLog(ctx, logger, workLog.Id, 1)
+				if err != nil {
+					t.Fatalf("GetWorkLog(%d) returned %v", workLog.Id, err)
+				}
+				if workLog.Duration != test.wantDurations[i] || stored.Duration != test.wantDurations[i] {
+					t.Errorf("work log %d has duration %d, stored %d, want %d", i+1, workLog.Duration, stored.Duration, test.wantDurations[i])
+				}
+				if !stored.StartTime.Equal(workLog.StartTime) || !stored.EndTime.Equal(workLog.EndTime) || stored.TaskId != workLog.TaskId {
+					t.Errorf("work log %d is stored as %+v, want %+v", i+1, stored, workLog)
+				}
+
+				storedPauses, err := db.GetPauses(ctx, logger, workLog.Id)
+				if err != nil {
+					t.Fatalf("GetPauses(%d) returned %v", workLog.Id, err)
+				}
+				wantPauses := [][]span{test.wantFirstPauses, test.wantSecondPauses}[i]
+				if !slices.EqualFunc(pauseSpans(storedPauses), wantPauses, spanEqual) {
+					t.Errorf("work log %d has pauses %v, want %v", i+1, pauseSpans(storedPauses), wantPauses)
+				}
+			}
+		})
+	}
+}
+
+func TestMergeWorkLogs(t *testing.T) {
+	type workLog struct {
+		taskId    uint64
+		objective string
+		start     time.Time
+		end       time.Time
+		pauses    []span
+	}
+
+	tests := []struct {
+		name          string
+		workLogs      []workLog // Given the ids 1, 2, ... in order
+		reviewed      []uint64  // Ids of the work logs that need a review
+		merge         []uint64
+		wantErr       error
+		wantEnd       time.Time
+		wantDuration  int64
+		wantObjective string
+		wantPauses    []span
+	}{
+		{
+			name: "adjacent",
+			workLogs: []workLog{
+				{taskId: 1, objective: "a", start: clock(10, 0), end: clock(11, 0)},
+				{taskId: 1, objective: "b", start: clock(11, 0), end: clock(12, 0)},
+			},
+			merge:         []uint64{1, 2},
+			wantEnd:       clock(12, 0),
+			wantDuration:  2 * 60 * 60,
+			wantObjective: "a; b",
+			wantPauses:    []span{},
+		},
+		{
+			name: "gap becomes a pause",
+			workLogs: []workLog{
+				{taskId: 1, objective: "a", start: clock(10, 0), end: clock(11, 0)},
+				{taskId: 1, objective: "a", start: clock(11, 30), end: clock(12, 0)},
+			},
+			merge:         []uint64{2, 1},
+			wantEnd:       clock(12, 0),
+			wantDuration:  90 * 60,
+			wantObjective: "a",
+			wantPauses:    []span{{clock(11, 0), clock(11, 30)}},
+		},
+		{
+			name: "pauses are kept",
+			workLogs: []workLog{
+				{taskId: 1, objective: "a", start: clock(10, 0), end: clock(11, 0), pauses: []span{{clock(10, 10), clock(10, 20)}}},
+				{taskId: 1, objective: "a", start: clock(11, 0), end: clock(12, 0), pauses: []span{{clock(11, 40), clock(11, 50)}}},
+			},
+			merge:         []uint64{1, 2},
+			wantEnd:       clock(12, 0),
+			wantDuration:  100 * 60,
+			wantObjective: "a",
+			wantPauses:    []span{{clock(10, 10), clock(10, 20)}, {clock(11, 40), clock(11, 50)}},
+		},
+		{
+			name: "work log in between",
+			workLogs: []workLog{
+				{taskId: 1, objective: "a", start: clock(10, 0), end: clock(11, 0)},
+				{taskId: 2, objective: "b", start: clock(11, 0), end: clock(11, 30)},
+				{taskId: 1, objective: "a", start: clock(11, 30), end: clock(12, 0)},
+			},
+			merge:   []uint64{1, 3},
+			wantErr: util.ErrMergeNotAdjacent,
+		},
+		{
+			name: "overlapping",
+			workLogs: []workLog{
+				{taskId: 1, objective: "a", start: clock(10, 0), end: clock(11, 0)},
+				{taskId: 1, objective: "a", start: clock(10, 30), end: clock(12, 0)},
+			},
+			merge:   []uint64{1, 2},
+			wantErr: util.ErrMergeNotAdjacent,
+		},
+		{
+			name: "different tasks",
+			workLogs: []workLog{
+				{taskId: 1, objective: "a", start: clock(10, 0), end: clock(11, 0)},
+				{taskId: 2, objective: "a", start: clock(11, 0), end: clock(12, 0)},
+			},
+			merge:   []uint64{1, 2},
+			wantErr: util.ErrMergeTaskMismatch,
+		},
+		{
+			name: "same work log twice",
+			workLogs: []workLog{
+				{taskId: 1, objective: "a", start: clock(10, 0), end: clock(11, 0)},
+			},
+			merge:   []uint64{1, 1},
+			wantErr: util.ErrMergeTooFew,
+		},
+		{
+			name: "work log needs a review",
+			workLogs: []workLog{
+				{taskId: 1, objective: "a", start: clock(10, 0), end: clock(11, 0)},
+				{taskId: 1, objective: "a", start: clock(11, 0), end: clock(12, 0)},
+			},
+			reviewed: []uint64{2},
+			merge:    []uint64{1, 2},
+			wantErr:  util.ErrMergeReviewPending,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+			logger := testLogger()
+			s, db := newWorkLogTestService(t)
+
+			for _, workLog := range test.workLogs {
+				addCompleteWorkLog(t, db, workLog.taskId, workLog.objective, workLog.start, workLog.end, workLog.pauses)
+			}
+			for _, id := range test.reviewed {
+				query := "INSERT INTO work_log_reviews (work_log_id, reason, auto_stop_time) SELECT id, ?, end_time FROM work_logs WHERE id = ?;"
+				if _, err := db.ExecContext(ctx, query, util.REVIEW_MAX_DURATION, id); err != nil {
+					t.Fatalf("adding the review of %d returned %v", id, err)
+				}
+			}
+
+			merged, err := s.MergeWorkLogs(ctx, logger, 1, test.merge)
+			if test.wantErr != nil {
+				if !errors.Is(err, test.wantErr) {
+					t.Fatalf("MergeWorkLogs returned %v, want %v", err, test.wantErr)
+				}
+				for id := range test.workLogs {
+					if _, err := db.GetWorkLog(ctx, logger, uint64(id+1), 1); err != nil {
+						t.Errorf("GetWorkLog(%d) after a failed merge returned %v", id+1, err)
+					}
+				}
+				for _, id := range test.reviewed {
+					if _, err := db.GetWorkLogReview(ctx, logger, id, 1); err != nil {
+						t.Errorf("GetWorkLogReview(%d) after a failed merge returned %v", id, err)
+					}
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("MergeWorkLogs returned %v", err)
+			}
+
+			first := test.workLogs[0]
+			if merged.Id != 1 || !merged.StartTime.Equal(first.start) || !merged.EndTime.Equal(test.wantEnd) {
+				t.Errorf("merged work log %d runs from %v to %v, want 1 from %v to %v", merged.Id, merged.StartTime, merged.EndTime, first.start, test.wantEnd)
+			}
+			if merged.Duration != test.wantDuration || merged.Objective != test.wantObjective {
+				t.Errorf("merged work log has duration %d and objective %q, want %d and %q", merged.Duration, merged.Objective, test.wantDuration, test.wantObjective)
+			}
+
+			stored, err := db.GetWorkLog(ctx, logger, merged.Id, 1)
+			if err != nil {
+				t.Fatalf("GetWorkLog(%d) returned %v", merged.Id, err)
+			}
+			if stored.Duration != merged.Duration || !stored.EndTime.Equal(merged.EndTime) || stored.Objective != merged.Objective {
+				t.Errorf("merged work log is stored as %+v, want %+v", stored, merged)
+			}
+			for id := 2; id <= len(test.workLogs); id++ {
+				if _, err := db.GetWorkLog(ctx, logger, uint64(id), 1); !errors.Is(err, util.ErrWorkLogNotFound) {
+					t.Errorf("GetWorkLog(%d) after the merge returned %v, want %v", id, err, util.ErrWorkLogNotFound)
+				}
+			}
+
+			storedPauses, err := db.GetPauses(ctx, logger, merged.Id)
+			if err != nil {
+				t.Fatalf("GetPauses(%d) returned %v", merged.Id, err)
+			}
+			if !slices.EqualFunc(pauseSpans(storedPauses), test.wantPauses, spanEqual) {
+				t.Errorf("merged work log has pauses %v, want %v", pauseSpans(storedPauses), test.wantPauses)
+			}
+		})
+	}
+}
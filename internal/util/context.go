@@ -0,0 +1,25 @@
+package util
+
+import (
+	"context"
+	"log/slog"
+)
+
+type contextKey string
+
+const loggerKey contextKey = "logger"
+
+// WithLogger returns a copy of ctx that carries logger.
+func WithLogger(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey, logger)
+}
+
+// LoggerFromContext returns the request scoped logger stored in ctx.
+// If there is none the default logger is returned, so the result is always safe to use.
+func LoggerFromContext(ctx context.Context) *slog.Logger {
+	logger, ok := ctx.Value(loggerKey).(*slog.Logger)
+	if !ok {
+		return slog.Default()
+	}
+	return logger
+}
@@ -1,15 +1,142 @@
 package util
 
 import (
-	"errors"
+	"net/http"
+	"time"
 )
 
+// STATUS_CLIENT_CLOSED_REQUEST is the non standard status used when the client went away before the response was written.
+const STATUS_CLIENT_CLOSED_REQUEST = 499
+
+// Error is an error of the API that carries a stable machine readable code,
+// the HTTP status it should be reported with and a message that is safe to show to a user.
+// All the errors below are sentinels, so they can still be checked with errors.Is after being wrapped.
+type Error struct {
+	Code    string
+	Status  int
+	Message string
+}
+
+func NewError(code string, status int, message string) *Error {
+	return &Error{
+		Code:    code,
+		Status:  status,
+		Message: message,
+	}
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// RetryError wraps an error that goes away after waiting for After.
+// WriteProblem reports After in the Retry-After header.
+type RetryError struct {
+	Err   error
+	After time.Duration
+}
+
+func (e *RetryError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *RetryError) Unwrap() error {
+	return e.Err
+}
+
+// ConflictError wraps an error caused by existing resources that block the request.
+// WriteProblem reports the Conflict in the conflict member of the problem.
+type ConflictError struct {
+	Err      error
+	Conflict any
+}
+
+func (e *ConflictError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ConflictError) Unwrap() error {
+	return e.Err
+}
+
 var (
-	ErrHashMismatch      = errors.New("Hash of given password and account hash is mismatched")
-	ErrBadPassword       = errors.New("Password should be at least 8 characters")
-	ErrEmptyString       = errors.New("An empty string is invalid")
-	ErrInvalidUserId     = errors.New("Invalid user id")
-	ErrInvalidCategoryId = errors.New("Invalid category id")
-	ErrSessionExpired    = errors.New("Session has expired")
-	ErrDatabase          = errors.New("Database Error")
+	ErrHashMismatch          = NewError("hash_mismatch", http.StatusUnauthorized, "Hash of given password and account hash is mismatched")
+	ErrBadPassword           = NewError("bad_password", http.StatusBadRequest, "Password should be at least 8 characters")
+	ErrEmptyString           = NewError("empty_string", http.StatusBadRequest, "An empty string is invalid")
+	ErrInvalidUserId         = NewError("invalid_user_id", http.StatusUnauthorized, "Invalid user id")
+	ErrInvalidCategoryId     = NewError("invalid_category_id", http.StatusBadRequest, "Invalid category id")
+	ErrCategoryNotFound      = NewError("category_not_found", http.StatusNotFound, "Category could not be found")
+	ErrCategoryExists        = NewError("category_exists", http.StatusConflict, "A category with that name already exists")
+	ErrInvalidTaskId         = NewError("invalid_task_id", http.StatusBadRequest, "Invalid task id")
+	ErrTaskNotFound          = NewError("task_not_found", http.StatusNotFound, "Task could not be found")
+	ErrInvalidDeadline       = NewError("invalid_deadline", http.StatusBadRequest, "Deadline must be after the creation time of the task")
+	ErrInvalidWorkLogId      = NewError("invalid_work_log_id", http.StatusBadRequest, "Invalid work log id")
+	ErrWorkLogNotFound       = NewError("work_log_not_found", http.StatusNotFound, "Work log could not be found")
+	ErrWorkLogStopped        = NewError("work_log_stopped", http.StatusConflict, "Work log has already been stopped")
+	ErrWorkLogPaused         = NewError("work_log_paused", http.StatusConflict, "Work log is already paused")
+	ErrWorkLogNotPaused      = NewError("work_log_not_paused", http.StatusConflict, "Work log is not paused")
+	ErrWorkLogConflict       = NewError("work_log_conflict", http.StatusConflict, "Another work log is already running")
+	ErrWorkLogOverlap        = NewError("work_log_overlap", http.StatusConflict, "Work log overlaps other work logs")
+	ErrWorkLogInFuture       = NewError("work_log_in_future", http.StatusBadRequest, "Work log can not end in the future")
+	ErrWorkLogNotStopped     = NewError("work_log_not_stopped", http.StatusConflict, "Work log must be stopped first")
+	ErrInvalidSplitTime      = NewError("invalid_split_time", http.StatusBadRequest, "Split time must be between the start and end of the work log")
+	ErrMergeTooFew           = NewError("merge_too_few", http.StatusBadRequest, "Between 2 and 50 distinct work logs are needed to merge")
+	ErrMergeTaskMismatch     = NewError("merge_task_mismatch", http.StatusConflict, "Merged work logs must be on the same task")
+	ErrMergeNotAdjacent      = NewError("merge_not_adjacent", http.StatusConflict, "Merged work logs must follow each other without other work logs in between")
+	ErrMergeReviewPending    = NewError("merge_review_pending", http.StatusConflict, "Work logs that need a review must be reviewed before they are merged")
+	ErrWorkLogRunning        = NewError("work_log_running", http.StatusConflict, "Work log is still running, pause it instead of taking a break")
+	ErrInvalidBreakId        = NewError("invalid_break_id", http.StatusBadRequest, "Invalid break id")
+	ErrBreakNotFound         = NewError("break_not_found", http.StatusNotFound, "Break could not be found")
+	ErrBreakRunning          = NewError("break_running", http.StatusConflict, "A break is already running")
+	ErrBreakEnded            = NewError("break_ended", http.StatusConflict, "Break has already ended")
+	ErrPomodoroNotFound      = NewError("pomodoro_not_found", http.StatusNotFound, "No pomodoro has been started")
+	ErrPomodoroFocusing      = NewError("pomodoro_focusing", http.StatusConflict, "A pomodoro focus phase is already running")
+	ErrPomodoroChanged       = NewError("pomodoro_changed", http.StatusConflict, "The pomodoro was changed by another request, try again")
+	ErrInvalidPomodoro       = NewError("invalid_pomodoro_settings", http.StatusBadRequest, "Pomodoro lengths must be between 1 minute and 24 hours and there must be at least 1 cycle before a long break")
+	ErrInvalidThreshold      = NewError("invalid_idle_threshold", http.StatusBadRequest, "Idle threshold must be between 1 minute and 24 hours")
+	ErrInvalidTimeZone       = NewError("invalid_time_zone", http.StatusBadRequest, "Time zone must be an IANA time zone name")
+	ErrInvalidLimits         = NewError("invalid_timer_limits", http.StatusBadRequest, "Maximum duration must be 0 or between 1 minute and 24 hours and the cut-off time must be formatted as HH:MM")
+	ErrReviewNotFound        = NewError("review_not_found", http.StatusNotFound, "Work log does not need a review")
+	ErrInvalidReviewEnd      = NewError("invalid_review_end", http.StatusBadRequest, "Corrected end time must be between the start and the automatic stop of the work log")
+	ErrInvalidGoalId         = NewError("invalid_goal_id", http.StatusBadRequest, "Invalid goal id")
+	ErrGoalNotFound          = NewError("goal_not_found", http.StatusNotFound, "Goal could not be found")
+	ErrInvalidGoal           = NewError("invalid_goal", http.StatusBadRequest, "Goal value must be positive, fit within its period and be at most 100 percent for deadline goals")
+	ErrInvalidRestDay        = NewError("invalid_rest_day", http.StatusBadRequest, "Rest day must be a date formatted as 2006-01-02")
+	ErrRestDayNotFound       = NewError("rest_day_not_found", http.StatusNotFound, "Rest day could not be found")
+	ErrInvalidGroupId        = NewError("invalid_group_id", http.StatusBadRequest, "Invalid group id")
+	ErrGroupNotFound         = NewError("group_not_found", http.StatusNotFound, "Group could not be found")
+	ErrGroupPermission       = NewError("group_permission_denied", http.StatusForbidden, "Your role in the group does not allow this")
+	ErrInvalidRole           = NewError("invalid_role", http.StatusBadRequest, "Role must be admin, member or viewer")
+	ErrInvalidTransfer       = NewError("invalid_transfer", http.StatusBadRequest, "Ownership can only be transferred to another member of the group")
+	ErrOwnerRole             = NewError("owner_role", http.StatusConflict, "The role of the owner can only change by transferring ownership")
+	ErrGroupCategoryId       = NewError("invalid_group_category_id", http.StatusBadRequest, "Invalid group category id")
+	ErrGroupCategoryNotFound = NewError("group_category_not_found", http.StatusNotFound, "Shared category could not be found")
+	ErrAlreadyMember         = NewError("already_member", http.StatusConflict, "User is already a member of the group")
+	ErrMemberNotFound        = NewError("member_not_found", http.StatusNotFound, "User is not a member of the group")
+	ErrOwnerCannotLeave      = NewError("owner_cannot_leave", http.StatusConflict, "The owner can not leave the group, delete it instead")
+	ErrInvalidInviteId       = NewError("invalid_invite_id", http.StatusBadRequest, "Invalid invite id")
+	ErrInvalidInvite         = NewError("invalid_invite", http.StatusBadRequest, "Invites must expire within 30 days")
+	ErrInviteNotFound        = NewError("invite_not_found", http.StatusNotFound, "Invite could not be found or is no longer valid")
+	ErrInvalidPeriod         = NewError("invalid_period", http.StatusBadRequest, "Period must be day, week or month")
+	ErrInvalidTimeRange      = NewError("invalid_time_range", http.StatusBadRequest, "Start of the time range must be before its end")
+	ErrSessionExpired        = NewError("session_expired", http.StatusUnauthorized, "Session has expired")
+	ErrUserNotFound          = NewError("user_not_found", http.StatusNotFound, "User could not be found")
+	ErrUsernameTaken         = NewError("username_taken", http.StatusConflict, "Username is already taken")
+	ErrDatabase              = NewError("database_error", http.StatusInternalServerError, "Database Error")
+	ErrSchemaMissing         = NewError("schema_missing", http.StatusServiceUnavailable, "Database schema is missing")
+	ErrRequestCanceled       = NewError("request_canceled", STATUS_CLIENT_CLOSED_REQUEST, "Request was cancelled by the client")
+	ErrRequestTimeout        = NewError("request_timeout", http.StatusServiceUnavailable, "Request took too long to process")
+
+	ErrInvalidCredentials = NewError("invalid_credentials", http.StatusUnauthorized, "Invalid username or password")
+	ErrLoginThrottled     = NewError("login_throttled", http.StatusTooManyRequests, "Too many failed logins, try again later")
+	ErrAccountLocked      = NewError("account_locked", http.StatusTooManyRequests, "Account is temporarily locked because of too many failed logins")
+	ErrAdminDisabled      = NewError("admin_disabled", http.StatusNotFound, "Admin endpoints are disabled")
+	ErrInvalidAdminKey    = NewError("invalid_admin_key", http.StatusForbidden, "Invalid admin key")
+	ErrMissingToken       = NewError("missing_token", http.StatusUnauthorized, "No Bearer token")
+	ErrTokenExpired       = NewError("token_expired", http.StatusUnauthorized, "Token is expired")
+	ErrMalformedToken     = NewError("malformed_token", http.StatusUnauthorized, "Token is malformed")
+	ErrMalformedBody      = NewError("malformed_body", http.StatusBadRequest, "Request body is malformed")
+	ErrInvalidPathId      = NewError("invalid_path_id", http.StatusBadRequest, "Id in the path must be a positive integer")
+	ErrInvalidQuery       = NewError("invalid_query", http.StatusBadRequest, "Invalid query parameter")
+	ErrInternal           = NewError("internal_error", http.StatusInternalServerError, "Unable to process the request")
 )
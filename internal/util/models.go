@@ -1,6 +1,9 @@
 package util
 
-import "time"
+import (
+	"fmt"
+	"time"
+)
 
 const (
 	SORT_CREATION uint8 = iota + 1 // Reserve 0 for no op
@@ -17,6 +20,13 @@ type Session struct {
 	UserId         uint64
 }
 
+type LoginAttempt struct {
+	Key         string
+	Failures    uint32
+	LastFailure time.Time
+	LockedUntil time.Time
+}
+
 type User struct {
 	Id           uint64
 	Username     string
@@ -24,6 +34,114 @@ type User struct {
 	CreationTime time.Time
 }
 
+type UserSettings struct {
+	TimeZone string `json:"timeZone"` // IANA name, empty for UTC
+	UserId   uint64 `json:"-"`
+}
+
+// Group is a team of users tracking their time together.
+// What a member can do in the group depends on the role of the membership.
+type Group struct {
+	Id      uint64 `json:"id"`
+	Name    string `json:"name"`
+	OwnerId uint64 `json:"ownerId"`
+}
+
+// GroupRole is stored as an integer and named in JSON. Higher roles rank above lower ones.
+type GroupRole uint8
+
+const (
+	ROLE_VIEWER GroupRole = iota + 1 // Reserve 0 for no role
+	ROLE_MEMBER
+	ROLE_ADMIN
+	ROLE_OWNER
+)
+
+var groupRoleNames = map[GroupRole]string{
+	ROLE_VIEWER: "viewer",
+	ROLE_MEMBER: "member",
+	ROLE_ADMIN:  "admin",
+	ROLE_OWNER:  "owner",
+}
+
+func (r GroupRole) MarshalText() ([]byte, error) {
+	name, ok := groupRoleNames[r]
+	if !ok {
+		return nil, fmt.Errorf("unknown group role %d", r)
+	}
+	return []byte(name), nil
+}
+
+func (r *GroupRole) UnmarshalText(text []byte) error {
+	for role, name := range groupRoleNames {
+		if name == string(text) {
+			*r = role
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown group role %q", text)
+}
+
+type GroupMember struct {
+	Id       uint64    `json:"id"`
+	GroupId  uint64    `json:"groupId"`
+	UserId   uint64    `json:"userId"`
+	Username string    `json:"username"`
+	Role     GroupRole `json:"role"`
+}
+
+// GroupCategory is a category shared by the members of a group.
+type GroupCategory struct {
+	Id      uint64 `json:"id"`
+	Name    string `json:"name"`
+	GroupId uint64 `json:"groupId"`
+}
+
+// MemberTime is the focused time of a group member from From up to To.
+type MemberTime struct {
+	UserId   uint64    `json:"userId"`
+	From     time.Time `json:"from"`
+	To       time.Time `json:"to"`
+	Duration int64     `json:"duration"` // Focused seconds of the completed work logs started within the range
+}
+
+// LeaderboardEntry is the place of a group member in a leaderboard.
+type LeaderboardEntry struct {
+	Rank            uint32 `json:"rank"` // Members with the same totals share a rank
+	UserId          uint64 `json:"userId"`
+	Username        string `json:"username"`
+	FocusedDuration int64  `json:"focusedDuration"` // Focused seconds of the completed work logs started within the period
+	CompletedTasks  int64  `json:"completedTasks"`
+}
+
+// Leaderboard ranks the members of a group by focused time and then completed tasks within a period.
+type Leaderboard struct {
+	GroupId     uint64             `json:"groupId"`
+	Period      string             `json:"period"`
+	PeriodStart time.Time          `json:"periodStart"`
+	PeriodEnd   time.Time          `json:"periodEnd"`
+	Entries     []LeaderboardEntry `json:"entries"`
+}
+
+// LeaderboardPrivacy is if a user is left out of the leaderboards of every group they are in.
+// Opted out users also hide their member time from the other members of their groups.
+type LeaderboardPrivacy struct {
+	OptedOut bool `json:"optedOut"`
+}
+
+// GroupInvite lets users join a group with its code until it expires, is revoked or has been used MaxUses times.
+// Only the hash of the code is stored, so the code is only known when the invite is created.
+type GroupInvite struct {
+	Id             uint64    `json:"id"`
+	GroupId        uint64    `json:"groupId"`
+	Code           string    `json:"code,omitempty"`
+	HashedCode     string    `json:"-"`
+	MaxUses        uint32    `json:"maxUses"` // 0 for unlimited uses
+	Uses           uint32    `json:"uses"`
+	ExpirationTime time.Time `json:"expirationTime"`
+	CreationTime   time.Time `json:"creationTime"`
+}
+
 type Tokens struct {
 	Access  string `json:"access"`
 	Refresh string `json:"refresh"`
@@ -35,15 +153,98 @@ type Category struct {
 	UserId uint64 `json:"userId"`
 }
 
+// GoalType is stored as an integer and named in JSON.
+type GoalType uint8
+
+// Time goals are in focused seconds, task goals in completed tasks
+// and deadline goals in the percentage of tasks due in the period that were completed before their deadline.
+const (
+	GOAL_DAILY_TIME GoalType = iota + 1 // Reserve 0 for no type
+	GOAL_WEEKLY_TIME
+	GOAL_MONTHLY_TIME
+	GOAL_DAILY_TASKS
+	GOAL_WEEKLY_TASKS
+	GOAL_MONTHLY_TASKS
+	GOAL_DAILY_DEADLINES
+	GOAL_WEEKLY_DEADLINES
+	GOAL_MONTHLY_DEADLINES
+)
+
+var goalTypeNames = map[GoalType]string{
+	GOAL_DAILY_TIME:        "dailyTime",
+	GOAL_WEEKLY_TIME:       "weeklyTime",
+	GOAL_MONTHLY_TIME:      "monthlyTime",
+	GOAL_DAILY_TASKS:       "dailyTasks",
+	GOAL_WEEKLY_TASKS:      "weeklyTasks",
+	GOAL_MONTHLY_TASKS:     "monthlyTasks",
+	GOAL_DAILY_DEADLINES:   "dailyDeadlines",
+	GOAL_WEEKLY_DEADLINES:  "weeklyDeadlines",
+	GOAL_MONTHLY_DEADLINES: "monthlyDeadlines",
+}
+
+func (t GoalType) MarshalText() ([]byte, error) {
+	name, ok := goalTypeNames[t]
+	if !ok {
+		return nil, fmt.Errorf("unknown goal type %d", t)
+	}
+	return []byte(name), nil
+}
+
+func (t *GoalType) UnmarshalText(text []byte) error {
+	for goalType, name := range goalTypeNames {
+		if name == string(text) {
+			*t = goalType
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown goal type %q", text)
+}
+
+type Goal struct {
+	Id         uint64   `json:"id"`
+	CategoryId uint64   `json:"categoryId"`
+	Value      int64    `json:"value"` // Target in the unit of the type
+	Type       GoalType `json:"type"`
+}
+
+// GoalProgress is how far the current period of a goal is done.
+type GoalProgress struct {
+	Goal
+	PeriodStart time.Time `json:"periodStart"`
+	PeriodEnd   time.Time `json:"periodEnd"`
+	Progress    int64     `json:"progress"`  // In the same unit as Value
+	Percent     int64     `json:"percent"`   // Progress as a percentage of Value
+	Projected   int64     `json:"projected"` // Progress expected by the end of the period at the current pace
+	IsComplete  bool      `json:"isComplete"`
+	OnTrack     bool      `json:"onTrack"` // Projected reaches Value
+}
+
+const (
+	PERIOD_DAY   = "day"
+	PERIOD_WEEK  = "week" // Starts on Monday
+	PERIOD_MONTH = "month"
+)
+
+// Streak is the amount of consecutive periods a goal was met in, or that focus time was logged in for the activity streak.
+// Periods that are all rest days do not break a streak.
+type Streak struct {
+	GoalId       uint64    `json:"goalId"` // 0 for the activity streak
+	Period       string    `json:"period"`
+	Current      uint32    `json:"current"` // Includes the current period once it is met
+	Longest      uint32    `json:"longest"`
+	CheckedUntil time.Time `json:"-"` // Start of the first period that has not been counted yet
+	UserId       uint64    `json:"-"`
+}
+
 type Task struct {
-	Id             uint64
-	Name           string
-	CreationTime   time.Time
-	CompletionTime time.Time
-	DeadlineTime   time.Time
-	IsComplete     bool
-	CategoryId     uint64
-	UserId         uint64
+	Id             uint64    `json:"id"`
+	Name           string    `json:"name"`
+	CreationTime   time.Time `json:"creationTime"`
+	CompletionTime time.Time `json:"completionTime"`
+	DeadlineTime   time.Time `json:"deadlineTime"`
+	IsComplete     bool      `json:"isComplete"`
+	CategoryId     uint64    `json:"categoryId"`
+	UserId         uint64    `json:"userId"`
 }
 
 type TaskQuerySettings struct {
@@ -54,3 +255,120 @@ type TaskQuerySettings struct {
 	Page      uint16
 	UserId    uint64
 }
+
+type WorkLog struct {
+	Id              uint64    `json:"id"`
+	TaskId          uint64    `json:"taskId"`
+	Objective       string    `json:"objective"`
+	WorkDescription string    `json:"workDescription"`
+	IsComplete      bool      `json:"isComplete"`
+	StartTime       time.Time `json:"startTime"`
+	Duration        int64     `json:"duration"` // Focused seconds excluding pauses, 0 until the work log is complete
+	EndTime         time.Time `json:"endTime"`
+	UserId          uint64    `json:"userId"`
+}
+
+// WorkLogInterval is the time a work log covers. The end of a running work log is the time it was read.
+type WorkLogInterval struct {
+	WorkLogId uint64    `json:"workLogId"`
+	StartTime time.Time `json:"startTime"`
+	EndTime   time.Time `json:"endTime"`
+}
+
+const (
+	REVIEW_MAX_DURATION = "maxDuration" // The work log was stopped after running for the maximum duration
+	REVIEW_CUTOFF       = "cutoff"      // The work log was stopped at the daily cut-off time
+)
+
+// TimerLimits stop forgotten work logs. A zero MaxDuration or empty CutoffTime disables that limit.
+type TimerLimits struct {
+	MaxDuration int64  `json:"maxDuration"` // Focused seconds
+	CutoffTime  string `json:"cutoffTime"`  // Formatted as 15:04 in the time zone of the user
+	UserId      uint64 `json:"-"`
+}
+
+// WorkLogReview is a work log that was stopped automatically and needs to be reviewed by the user.
+type WorkLogReview struct {
+	WorkLog
+	Reason       string    `json:"reason"`
+	AutoStopTime time.Time `json:"autoStopTime"`
+}
+
+type WorkLogQuerySettings struct {
+	TaskId uint64
+	From   time.Time // Inclusive lower bound of the start time, ignored if zero
+	To     time.Time // Exclusive upper bound of the start time, ignored if zero
+	Page   uint16
+	UserId uint64
+}
+
+type Pause struct {
+	Id        uint64    `json:"id"`
+	StartTime time.Time `json:"startTime"`
+	Duration  int64     `json:"duration"` // In seconds, 0 until the pause has ended
+	EndTime   time.Time `json:"endTime"`
+	WorkLogId uint64    `json:"workLogId"`
+}
+
+// WorkLogTimeline is a work log with its pauses.
+// The durations are in seconds and are calculated up to now for a running work log.
+type WorkLogTimeline struct {
+	WorkLog
+	Pauses          []Pause `json:"pauses"`
+	WallDuration    int64   `json:"wallDuration"`
+	PausedDuration  int64   `json:"pausedDuration"`
+	FocusedDuration int64   `json:"focusedDuration"`
+}
+
+// Heartbeat is the last time a client reported the user as active.
+// A running work log is paused once the user has been idle for IdleThreshold seconds.
+type Heartbeat struct {
+	LastHeartbeat time.Time `json:"lastHeartbeat"`
+	IdleThreshold int64     `json:"idleThreshold"`
+	IdleTime      time.Time `json:"idleTime"` // LastHeartbeat plus IdleThreshold
+	UserId        uint64    `json:"-"`
+}
+
+type Break struct {
+	Id        uint64    `json:"id"`
+	StartTime time.Time `json:"startTime"`
+	Duration  int64     `json:"duration"` // In seconds, 0 until the break has ended
+	EndTime   time.Time `json:"endTime"`
+	WorkLogId uint64    `json:"workLogId"`
+}
+
+type DailyTotal struct {
+	Date     string `json:"date"`     // Formatted as 2006-01-02
+	Duration int64  `json:"duration"` // In seconds
+}
+
+const (
+	POMODORO_FOCUS       = "focus"
+	POMODORO_SHORT_BREAK = "shortBreak"
+	POMODORO_LONG_BREAK  = "longBreak"
+	POMODORO_IDLE        = "idle" // The last break has ended and the next focus phase has not been started
+)
+
+// PomodoroSettings are the lengths of a user's Pomodoro phases in seconds.
+type PomodoroSettings struct {
+	FocusLength           int64  `json:"focusLength"`
+	ShortBreakLength      int64  `json:"shortBreakLength"`
+	LongBreakLength       int64  `json:"longBreakLength"`
+	CyclesBeforeLongBreak uint32 `json:"cyclesBeforeLongBreak"`
+	UserId                uint64 `json:"-"`
+}
+
+// Pomodoro is the current phase of a user's Pomodoro cycle.
+// A focus phase runs a work log and a break phase runs a break after it.
+type Pomodoro struct {
+	Phase      string    `json:"phase"`
+	Cycle      uint32    `json:"cycle"` // Amount of focus phases completed
+	TaskId     uint64    `json:"taskId"`
+	Objective  string    `json:"objective"`
+	PhaseStart time.Time `json:"phaseStart"`
+	PhaseEnd   time.Time `json:"phaseEnd"`  // Zero while idle
+	Remaining  int64     `json:"remaining"` // Seconds until the phase ends, calculated when the pomodoro is read
+	WorkLogId  uint64    `json:"workLogId"`
+	BreakId    uint64    `json:"breakId"`
+	UserId     uint64    `json:"-"`
+}
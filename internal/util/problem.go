@@ -0,0 +1,84 @@
+package util
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+)
+
+const PROBLEM_CONTENT_TYPE = "application/problem+json"
+
+// Problem is a RFC 7807 problem details object.
+// Code is an extension member holding the Code of the Error so clients can switch on it.
+// Conflict is an extension member holding the resources of a ConflictError.
+type Problem struct {
+	Type     string `json:"type"`
+	Title    string `json:"title"`
+	Status   int    `json:"status"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+	Code     string `json:"code"`
+	Conflict any    `json:"conflict,omitempty"`
+}
+
+// NewProblem creates the Problem for err.
+// If err does not wrap an Error it is reported as ErrInternal, so no internal error text is leaked to the client.
+func NewProblem(r *http.Request, err error) Problem {
+	apiErr := &Error{}
+	detail := ""
+	if errors.As(err, &apiErr) {
+		detail = err.Error()
+	} else {
+		apiErr = ErrInternal
+		detail = ErrInternal.Message
+	}
+
+	title := http.StatusText(apiErr.Status)
+	if title == "" {
+		title = apiErr.Message
+	}
+
+	problem := Problem{
+		Type:     "about:blank",
+		Title:    title,
+		Status:   apiErr.Status,
+		Detail:   detail,
+		Instance: r.URL.Path,
+		Code:     apiErr.Code,
+	}
+
+	conflictErr := &ConflictError{}
+	if errors.As(err, &conflictErr) {
+		problem.Conflict = conflictErr.Conflict
+	}
+
+	return problem
+}
+
+// WriteProblem writes err as an application/problem+json response.
+func WriteProblem(w http.ResponseWriter, r *http.Request, err error) {
+	problem := NewProblem(r, err)
+
+	level := slog.LevelDebug
+	if problem.Status >= http.StatusInternalServerError {
+		level = slog.LevelError
+	}
+	LoggerFromContext(r.Context()).LogAttrs(r.Context(), level, "Problem response",
+		slog.String("code", problem.Code),
+		slog.String("err", err.Error()),
+	)
+
+	responseBytes, _ := json.Marshal(problem)
+	retryErr := &RetryError{}
+	if errors.As(err, &retryErr) {
+		seconds := int64(math.Ceil(retryErr.After.Seconds()))
+		w.Header().Set("Retry-After", strconv.FormatInt(max(seconds, 1), 10))
+	}
+
+	w.Header().Set("Content-Type", PROBLEM_CONTENT_TYPE)
+	w.WriteHeader(problem.Status)
+	w.Write(responseBytes)
+}